@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/auth"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/client"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/credentials"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/ratelimit"
@@ -28,12 +28,10 @@ func main() {
 	fmt.Printf("Public Key: %s...\n", publicKey[:20])
 	fmt.Println("Use the public key to create API credentials on Robinhood")
 
-	// Get actual credentials from environment
-	apiKey := os.Getenv("ROBINHOOD_API_KEY")
-	actualPrivateKey := os.Getenv("ROBINHOOD_PRIVATE_KEY")
-
-	if apiKey == "" || actualPrivateKey == "" {
-		log.Fatal("Please set ROBINHOOD_API_KEY and ROBINHOOD_PRIVATE_KEY environment variables")
+	// Load actual credentials from the environment or ~/.robinhood/credentials
+	creds, err := credentials.Load("")
+	if err != nil {
+		log.Fatalf("Failed to load credentials: %v", err)
 	}
 
 	// Example 2: Create client with custom configuration
@@ -48,9 +46,8 @@ func main() {
 	rateLimiter := ratelimit.NewRateLimiter(50, 50, time.Minute)
 	
 	// Create client with custom options
-	c, err := client.New(
-		apiKey, 
-		actualPrivateKey,
+	c, err := client.NewFromCredentials(
+		creds,
 		client.WithHTTPClient(httpClient),
 		client.WithRateLimiter(rateLimiter),
 	)
@@ -70,7 +67,7 @@ func main() {
 		Side:          "buy",
 		Type:          "market",
 		MarketOrderConfig: &models.MarketOrderConfig{
-			AssetQuantity: -1, // Negative quantity
+			AssetQuantity: models.NewDecimalFromInt(-1), // Negative quantity
 		},
 	}
 
@@ -124,7 +121,7 @@ func main() {
 	}
 	
 	currentPrice := bidAsk.Results[0].Price
-	limitPrice := currentPrice * 0.90 // 10% below market (buy order)
+	limitPrice := currentPrice.Mul(models.MustParseDecimal("0.90")).Round(2) // 10% below market (buy order)
 	
 	// Demonstrating auto-generated UUID - no ClientOrderID field
 	monitorOrder := &models.PlaceOrderRequest{
@@ -133,7 +130,7 @@ func main() {
 		Side: "buy",
 		Type: "limit",
 		LimitOrderConfig: &models.LimitOrderConfig{
-			AssetQuantity: 0.0001,
+			AssetQuantity: models.MustParseDecimal("0.0001"),
 			LimitPrice:    limitPrice,
 			TimeInForce:   "gtc",
 		},
@@ -164,14 +161,14 @@ func main() {
 			}
 			
 			fmt.Printf("  Status: %s", status.State)
-			if status.FilledAssetQuantity > 0 {
+			if status.FilledAssetQuantity.Sign() > 0 {
 				fmt.Printf(" (Filled: %.8f @ $%.2f)", 
 					status.FilledAssetQuantity, 
 					status.AveragePrice)
 			}
 			fmt.Println()
 			
-			if status.State == "filled" || status.State == "canceled" {
+			if status.State.IsTerminal() {
 				fmt.Println("Order completed!")
 				goto cleanup
 			}
@@ -185,7 +182,7 @@ func main() {
 cleanup:
 	// Cancel the order if it's still open
 	status, err := c.Trading.GetOrder(ctx, order.ID)
-	if err == nil && status.State == "open" {
+	if err == nil && status.State == models.OrderStateOpen {
 		fmt.Println("Cancelling open order...")
 		err = c.Trading.CancelOrder(ctx, order.ID)
 		if err != nil {
@@ -209,7 +206,7 @@ cleanup:
 		Side:          "buy",
 		Type:          "market",
 		MarketOrderConfig: &models.MarketOrderConfig{
-			AssetQuantity: 0.001,
+			AssetQuantity: models.MustParseDecimal("0.001"),
 		},
 	}
 	
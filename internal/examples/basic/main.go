@@ -4,22 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/client"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/credentials"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
 )
 
 func main() {
-	// Get credentials from environment variables
-	apiKey := os.Getenv("ROBINHOOD_API_KEY")
-	privateKey := os.Getenv("ROBINHOOD_PRIVATE_KEY")
-
-	if apiKey == "" || privateKey == "" {
-		log.Fatal("Please set ROBINHOOD_API_KEY and ROBINHOOD_PRIVATE_KEY environment variables")
+	// Load credentials from the environment or ~/.robinhood/credentials
+	creds, err := credentials.Load("")
+	if err != nil {
+		log.Fatalf("Failed to load credentials: %v", err)
 	}
 
 	// Create a new client
-	c, err := client.New(apiKey, privateKey)
+	c, err := client.NewFromCredentials(creds)
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
@@ -54,7 +53,10 @@ func main() {
 
 	// Example 3: Get estimated prices for different quantities
 	fmt.Println("\n=== Estimated Prices ===")
-	estimates, err := c.MarketData.GetEstimatedPrice(ctx, "BTC-USD", "ask", 0.001, 0.01, 0.1)
+	estimates, err := c.MarketData.GetEstimatedPrice(ctx, "BTC-USD", "ask",
+		models.MustParseDecimal("0.001"),
+		models.MustParseDecimal("0.01"),
+		models.MustParseDecimal("0.1"))
 	if err != nil {
 		log.Printf("Failed to get estimated prices: %v", err)
 	} else {
@@ -4,23 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/client"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/credentials"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
 )
 
 func main() {
-	// Get API credentials from environment
-	apiKey := os.Getenv("ROBINHOOD_API_KEY")
-	privateKey := os.Getenv("ROBINHOOD_PRIVATE_KEY")
-
-	if apiKey == "" || privateKey == "" {
-		log.Fatal("Please set ROBINHOOD_API_KEY and ROBINHOOD_PRIVATE_KEY environment variables")
+	// Load credentials from the environment or ~/.robinhood/credentials
+	creds, err := credentials.Load("")
+	if err != nil {
+		log.Fatalf("Failed to load credentials: %v", err)
 	}
 
 	// Create client
-	c, err := client.New(apiKey, privateKey)
+	c, err := client.NewFromCredentials(creds)
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
@@ -51,9 +49,9 @@ func main() {
 		}
 	}
 
-	// Example 2: Get just the symbols
-	fmt.Println("=== Getting Symbol List ===")
-	symbols, err := c.GetAllTradeableSymbols(ctx)
+	// Example 2: Get just the symbols of pairs quoted in USD
+	fmt.Println("=== Getting USD Symbol List ===")
+	symbols, err := c.GetAllTradeableSymbols(ctx, client.FilterQuoteCode("USD"))
 	if err != nil {
 		log.Printf("Failed to fetch symbols: %v", err)
 	} else {
@@ -97,7 +95,7 @@ func main() {
 		Side: "buy",
 		Type: "market",
 		MarketOrderConfig: &models.MarketOrderConfig{
-			AssetQuantity: 0.0001,
+			AssetQuantity: models.MustParseDecimal("0.0001"),
 		},
 	}
 	
@@ -4,24 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/client"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/credentials"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
 )
 
 
 func main() {
-	// Get credentials from environment variables
-	apiKey := os.Getenv("ROBINHOOD_API_KEY")
-	privateKey := os.Getenv("ROBINHOOD_PRIVATE_KEY")
-
-	if apiKey == "" || privateKey == "" {
-		log.Fatal("Please set ROBINHOOD_API_KEY and ROBINHOOD_PRIVATE_KEY environment variables")
+	// Load credentials from the environment or ~/.robinhood/credentials
+	creds, err := credentials.Load("")
+	if err != nil {
+		log.Fatalf("Failed to load credentials: %v", err)
 	}
 
 	// Create a new client
-	c, err := client.New(apiKey, privateKey)
+	c, err := client.NewFromCredentials(creds)
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
@@ -36,7 +34,7 @@ func main() {
 		Side: "buy",
 		Type: "market",
 		MarketOrderConfig: &models.MarketOrderConfig{
-			AssetQuantity: 0.0001, // Buy 0.0001 BTC
+			AssetQuantity: models.MustParseDecimal("0.0001"), // Buy 0.0001 BTC
 		},
 	}
 
@@ -61,7 +59,7 @@ func main() {
 	}
 	
 	currentPrice := bidAsk.Results[0].Price
-	limitPrice := currentPrice * 1.01 // Set limit 1% above current price
+	limitPrice := currentPrice.Mul(models.MustParseDecimal("1.01")).Round(2) // Set limit 1% above current price
 
 	// You can still manually set ClientOrderID if you want to track it
 	manualUUID := "custom-" + fmt.Sprintf("%.2f", currentPrice) // Example of custom ID
@@ -71,7 +69,7 @@ func main() {
 		Side:          "sell",
 		Type:          "limit",
 		LimitOrderConfig: &models.LimitOrderConfig{
-			AssetQuantity: 0.0001,
+			AssetQuantity: models.MustParseDecimal("0.0001"),
 			LimitPrice:    limitPrice,
 			TimeInForce:   "gtc", // Good Till Cancelled
 		},
@@ -90,7 +88,7 @@ func main() {
 	// Example 3: Place a stop loss order with auto-generated UUID
 	fmt.Println("\n=== Placing Stop Loss Order (Auto-Generated UUID) ===")
 	
-	stopPrice := currentPrice * 0.95 // Stop loss at 5% below current price
+	stopPrice := currentPrice.Mul(models.MustParseDecimal("0.95")).Round(2) // Stop loss at 5% below current price
 
 	stopLossOrder := &models.PlaceOrderRequest{
 		Symbol: "BTC-USD",
@@ -98,7 +96,7 @@ func main() {
 		Side: "sell",
 		Type: "stop_loss",
 		StopLossOrderConfig: &models.StopLossOrderConfig{
-			AssetQuantity: 0.0001,
+			AssetQuantity: models.MustParseDecimal("0.0001"),
 			StopPrice:     stopPrice,
 			TimeInForce:   "gtc",
 		},
@@ -115,7 +113,7 @@ func main() {
 	}
 
 	// Example 4: Cancel an order
-	if order2 != nil && order2.State == "open" {
+	if order2 != nil && order2.State == models.OrderStateOpen {
 		fmt.Println("\n=== Cancelling Limit Order ===")
 		err = c.Trading.CancelOrder(ctx, order2.ID)
 		if err != nil {
@@ -133,7 +131,7 @@ func main() {
 			log.Printf("Failed to get order status: %v", err)
 		} else {
 			fmt.Printf("Order %s status: %s\n", updatedOrder.ID[:8], updatedOrder.State)
-			if updatedOrder.State == "filled" {
+			if updatedOrder.State == models.OrderStateFilled {
 				fmt.Printf("Filled quantity: %.8f\n", updatedOrder.FilledAssetQuantity)
 				fmt.Printf("Average price: %.2f\n", updatedOrder.AveragePrice)
 			}
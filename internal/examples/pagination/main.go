@@ -4,24 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/client"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/credentials"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
 )
 
 func main() {
-	// Get credentials from environment variables
-	apiKey := os.Getenv("ROBINHOOD_API_KEY")
-	privateKey := os.Getenv("ROBINHOOD_PRIVATE_KEY")
-
-	if apiKey == "" || privateKey == "" {
-		log.Fatal("Please set ROBINHOOD_API_KEY and ROBINHOOD_PRIVATE_KEY environment variables")
+	// Load credentials from the environment or ~/.robinhood/credentials
+	creds, err := credentials.Load("")
+	if err != nil {
+		log.Fatalf("Failed to load credentials: %v", err)
 	}
 
 	// Create a new client
-	c, err := client.New(apiKey, privateKey)
+	c, err := client.NewFromCredentials(creds)
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
@@ -55,21 +53,20 @@ func main() {
 		pageNum++
 	}
 
-	// Example 2: Get all holdings using pagination
-	fmt.Println("\n=== Getting All Holdings ===")
-	holdingsPaginator := c.Trading.NewHoldingsPaginator()
-	
-	allHoldings, err := holdingsPaginator.GetAllPages(ctx)
-	if err != nil {
-		log.Printf("Failed to get all holdings: %v", err)
-	} else {
-		fmt.Printf("Total holdings: %d\n", len(allHoldings))
-		for _, holding := range allHoldings {
-			if holding.TotalQuantity > 0 {
-				fmt.Printf("  %s: %.8f\n", holding.AssetCode, holding.TotalQuantity)
-			}
+	// Example 2: Iterate over all holdings, fetching pages as needed
+	fmt.Println("\n=== Iterating All Holdings ===")
+	totalHoldings := 0
+	for holding, err := range c.Trading.AllHoldings(ctx) {
+		if err != nil {
+			log.Printf("Failed to get holdings: %v", err)
+			break
+		}
+		totalHoldings++
+		if holding.TotalQuantity.Sign() > 0 {
+			fmt.Printf("  %s: %.8f\n", holding.AssetCode, holding.TotalQuantity)
 		}
 	}
+	fmt.Printf("Total holdings: %d\n", totalHoldings)
 
 	// Example 3: Paginate through filtered orders
 	fmt.Println("\n=== Paginating Recent Orders ===")
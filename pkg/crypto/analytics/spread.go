@@ -0,0 +1,357 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/client"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// Defaults for Config
+const (
+	defaultWindow        = 24 * time.Hour
+	defaultMaxSamples    = 10000
+	defaultBlowoutFactor = 3
+	defaultMinSamples    = 30
+)
+
+var (
+	half       = models.NewDecimal(5, -1)
+	bpsPerUnit = models.NewDecimalFromInt(10000)
+)
+
+// Config configures a SpreadTracker. Zero fields use defaults.
+type Config struct {
+	// Window is how long samples are kept for each symbol. Defaults to 24h.
+	Window time.Duration
+
+	// MaxSamples caps the samples kept for each symbol, dropping the oldest.
+	// Defaults to 10000.
+	MaxSamples int
+
+	// Location is the time zone of the time-of-day profile. Defaults to UTC.
+	Location *time.Location
+
+	// BlowoutFactor flags a spread wider than this many times the median
+	// spread as a blowout. Defaults to 3.
+	BlowoutFactor float64
+
+	// MinSamples is the number of samples a symbol needs before blowouts
+	// are flagged. Defaults to 30.
+	MinSamples int
+
+	// OnBlowout is called when a symbol's spread blows out, and not again
+	// until it has narrowed back below the threshold. It is called without
+	// the tracker's lock held, on the goroutine that added the quote.
+	OnBlowout func(Blowout)
+}
+
+// Blowout reports a spread much wider than usual
+type Blowout struct {
+	Symbol    string
+	At        time.Time
+	SpreadBps float64
+	MedianBps float64
+	Quote     models.BestBidAskResult
+}
+
+// Stats summarizes a symbol's spread, in basis points of the mid price
+type Stats struct {
+	Symbol  string
+	Samples int
+	From    time.Time
+	To      time.Time
+
+	Mean float64
+	Min  float64
+	Max  float64
+	P50  float64
+	P90  float64
+	P99  float64
+}
+
+// HourProfile is the spread during one hour of the day
+type HourProfile struct {
+	Hour    int
+	Samples int
+	Mean    float64
+}
+
+// RoundTrip is the cost of buying a quantity and selling it straight back
+type RoundTrip struct {
+	Quantity models.Decimal
+
+	// Cost is the quote currency lost to the spread
+	Cost models.Decimal
+
+	// CostBps is Cost in basis points of the quantity's value at mid
+	CostBps float64
+}
+
+// SpreadTracker records the spread of every quote it is given and reports
+// statistics per symbol. It is safe for concurrent use.
+type SpreadTracker struct {
+	config Config
+
+	mu      sync.Mutex
+	symbols map[string]*spreadHistory
+}
+
+// spreadHistory is the samples for one symbol, oldest first, and their
+// spreads kept in increasing order so percentiles need no sort
+type spreadHistory struct {
+	samples []spreadSample
+	sorted  []float64
+	last    models.BestBidAskResult
+	blown   bool
+}
+
+type spreadSample struct {
+	at  time.Time
+	bps float64
+}
+
+// NewSpreadTracker creates a tracker
+func NewSpreadTracker(config Config) *SpreadTracker {
+	if config.Window <= 0 {
+		config.Window = defaultWindow
+	}
+	if config.MaxSamples <= 0 {
+		config.MaxSamples = defaultMaxSamples
+	}
+	if config.Location == nil {
+		config.Location = time.UTC
+	}
+	if config.BlowoutFactor <= 0 {
+		config.BlowoutFactor = defaultBlowoutFactor
+	}
+	if config.MinSamples <= 0 {
+		config.MinSamples = defaultMinSamples
+	}
+
+	return &SpreadTracker{
+		config:  config,
+		symbols: make(map[string]*spreadHistory),
+	}
+}
+
+// SpreadBps returns the spread between a quote's bid and ask, inclusive of
+// the buy and sell spreads, in basis points of the mid price. It returns
+// false if the quote is not two-sided.
+func SpreadBps(quote models.BestBidAskResult) (float64, bool) {
+	bid := quote.BidInclusiveOfSellSpread
+	ask := quote.AskInclusiveOfBuySpread
+	if bid.Sign() <= 0 || ask.Sign() <= 0 {
+		return 0, false
+	}
+	mid := bid.Add(ask).Mul(half)
+	return ask.Sub(bid).Mul(bpsPerUnit).Div(mid, 4).Float64(), true
+}
+
+// RoundTripCost returns the cost of buying quantity at the quote's ask and
+// selling it at the bid
+func RoundTripCost(quote models.BestBidAskResult, quantity models.Decimal) RoundTrip {
+	bid := quote.BidInclusiveOfSellSpread
+	ask := quote.AskInclusiveOfBuySpread
+	trip := RoundTrip{
+		Quantity: quantity,
+		Cost:     quantity.Mul(ask.Sub(bid)),
+	}
+	if bps, ok := SpreadBps(quote); ok {
+		trip.CostBps = bps
+	}
+	return trip
+}
+
+// Add records a quote received at the given time. One-sided quotes are
+// ignored.
+func (t *SpreadTracker) Add(quote models.BestBidAskResult, at time.Time) {
+	bps, ok := SpreadBps(quote)
+	if !ok {
+		return
+	}
+	symbol := strings.ToUpper(quote.Symbol)
+
+	t.mu.Lock()
+	h, ok := t.symbols[symbol]
+	if !ok {
+		h = &spreadHistory{}
+		t.symbols[symbol] = h
+	}
+
+	var blowout *Blowout
+	if len(h.samples) >= t.config.MinSamples {
+		median := percentile(h.sorted, 50)
+		wide := bps > median*t.config.BlowoutFactor
+		if wide && !h.blown {
+			blowout = &Blowout{Symbol: symbol, At: at, SpreadBps: bps, MedianBps: median, Quote: quote}
+		}
+		h.blown = wide
+	}
+
+	h.last = quote
+	h.samples = append(h.samples, spreadSample{at: at, bps: bps})
+	i, _ := slices.BinarySearch(h.sorted, bps)
+	h.sorted = slices.Insert(h.sorted, i, bps)
+	t.trim(h, at)
+	t.mu.Unlock()
+
+	if blowout != nil && t.config.OnBlowout != nil {
+		t.config.OnBlowout(*blowout)
+	}
+}
+
+// Run records quote updates until events is closed or ctx is done
+func (t *SpreadTracker) Run(ctx context.Context, events <-chan client.QuoteEvent) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if event.Type == client.QuoteUpdate {
+				t.Add(event.Quote, event.Received)
+			}
+		}
+	}
+}
+
+// Symbols returns the symbols with samples, sorted
+func (t *SpreadTracker) Symbols() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	symbols := make([]string, 0, len(t.symbols))
+	for symbol := range t.symbols {
+		symbols = append(symbols, symbol)
+	}
+	slices.Sort(symbols)
+	return symbols
+}
+
+// Stats returns spread statistics for symbol, and false if it has no
+// samples
+func (t *SpreadTracker) Stats(symbol string) (Stats, bool) {
+	symbol = strings.ToUpper(symbol)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.symbols[symbol]
+	if !ok || len(h.samples) == 0 {
+		return Stats{}, false
+	}
+
+	spreads := h.sorted
+	sum := 0.0
+	for _, bps := range spreads {
+		sum += bps
+	}
+
+	return Stats{
+		Symbol:  symbol,
+		Samples: len(spreads),
+		From:    h.samples[0].at,
+		To:      h.samples[len(h.samples)-1].at,
+		Mean:    sum / float64(len(spreads)),
+		Min:     spreads[0],
+		Max:     spreads[len(spreads)-1],
+		P50:     percentile(spreads, 50),
+		P90:     percentile(spreads, 90),
+		P99:     percentile(spreads, 99),
+	}, true
+}
+
+// Percentile returns the p-th percentile spread of symbol in basis points,
+// for p from 0 to 100, and false if it has no samples
+func (t *SpreadTracker) Percentile(symbol string, p float64) (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.symbols[strings.ToUpper(symbol)]
+	if !ok || len(h.samples) == 0 {
+		return 0, false
+	}
+	return percentile(h.sorted, p), true
+}
+
+// TimeOfDay returns symbol's mean spread for each hour of the day in the
+// configured location. Hours without samples have a zero mean.
+func (t *SpreadTracker) TimeOfDay(symbol string) [24]HourProfile {
+	var profile [24]HourProfile
+	for hour := range profile {
+		profile[hour].Hour = hour
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.symbols[strings.ToUpper(symbol)]
+	if !ok {
+		return profile
+	}
+
+	for _, sample := range h.samples {
+		p := &profile[sample.at.In(t.config.Location).Hour()]
+		p.Samples++
+		p.Mean += (sample.bps - p.Mean) / float64(p.Samples)
+	}
+	return profile
+}
+
+// RoundTripCost estimates the cost of buying quantity of symbol and selling
+// it straight back, at the p-th percentile of its recorded spreads and its
+// latest mid price. Use 50 for a typical cost and a higher percentile for a
+// cautious one. It returns false if the symbol has no samples.
+func (t *SpreadTracker) RoundTripCost(symbol string, quantity models.Decimal, p float64) (RoundTrip, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.symbols[strings.ToUpper(symbol)]
+	if !ok || len(h.samples) == 0 {
+		return RoundTrip{}, false
+	}
+
+	bps := percentile(h.sorted, p)
+	mid := h.last.BidInclusiveOfSellSpread.Add(h.last.AskInclusiveOfBuySpread).Mul(half)
+	cost := quantity.Mul(mid).Mul(models.NewDecimalFromFloat(bps)).Div(bpsPerUnit, mid.Scale()+2)
+	return RoundTrip{Quantity: quantity, Cost: cost, CostBps: bps}, true
+}
+
+// trim drops samples outside the window or over the cap. The lock must be
+// held.
+func (t *SpreadTracker) trim(h *spreadHistory, now time.Time) {
+	cutoff := now.Add(-t.config.Window)
+	drop := 0
+	for drop < len(h.samples) && h.samples[drop].at.Before(cutoff) {
+		drop++
+	}
+	drop = max(drop, len(h.samples)-t.config.MaxSamples)
+	if drop <= 0 {
+		return
+	}
+	for _, sample := range h.samples[:drop] {
+		i, _ := slices.BinarySearch(h.sorted, sample.bps)
+		h.sorted = slices.Delete(h.sorted, i, i+1)
+	}
+	h.samples = slices.Delete(h.samples, 0, drop)
+}
+
+// percentile interpolates the p-th percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	p = min(max(p, 0), 100)
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
@@ -0,0 +1,173 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/client"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+var t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// quoteWithSpread returns a quote around a mid of 10000 whose spread is bps
+// basis points
+func quoteWithSpread(symbol string, bps int64) models.BestBidAskResult {
+	halfSpread := models.NewDecimalFromInt(bps).Div(models.NewDecimalFromInt(2), 1)
+	mid := models.NewDecimalFromInt(10000)
+	return models.BestBidAskResult{
+		Symbol:                   symbol,
+		BidInclusiveOfSellSpread: mid.Sub(halfSpread),
+		AskInclusiveOfBuySpread:  mid.Add(halfSpread),
+	}
+}
+
+func TestSpreadBps(t *testing.T) {
+	if bps, ok := SpreadBps(quoteWithSpread("BTC-USD", 25)); !ok || bps != 25 {
+		t.Errorf("SpreadBps() = %v, %v, want 25", bps, ok)
+	}
+	if _, ok := SpreadBps(models.BestBidAskResult{Symbol: "BTC-USD"}); ok {
+		t.Error("SpreadBps() of an empty quote succeeded")
+	}
+}
+
+func TestSpreadTracker_Stats(t *testing.T) {
+	tracker := NewSpreadTracker(Config{})
+	for i := int64(1); i <= 100; i++ {
+		tracker.Add(quoteWithSpread("btc-usd", i), t0.Add(time.Duration(i)*time.Second))
+	}
+
+	stats, ok := tracker.Stats("BTC-USD")
+	if !ok {
+		t.Fatal("Stats() found no samples")
+	}
+	if stats.Samples != 100 || stats.Min != 1 || stats.Max != 100 || stats.Mean != 50.5 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if math.Abs(stats.P50-50.5) > 1e-9 || math.Abs(stats.P90-90.1) > 1e-9 || math.Abs(stats.P99-99.01) > 1e-9 {
+		t.Errorf("percentiles = %v, %v, %v, want 50.5, 90.1, 99.01", stats.P50, stats.P90, stats.P99)
+	}
+	if !stats.From.Equal(t0.Add(time.Second)) || !stats.To.Equal(t0.Add(100*time.Second)) {
+		t.Errorf("From, To = %v, %v", stats.From, stats.To)
+	}
+
+	if _, ok := tracker.Stats("ETH-USD"); ok {
+		t.Error("Stats() for an unknown symbol succeeded")
+	}
+}
+
+func TestSpreadTracker_Window(t *testing.T) {
+	tracker := NewSpreadTracker(Config{Window: time.Hour, MaxSamples: 3})
+
+	tracker.Add(quoteWithSpread("BTC-USD", 100), t0)
+	for i := int64(1); i <= 4; i++ {
+		tracker.Add(quoteWithSpread("BTC-USD", i), t0.Add(2*time.Hour+time.Duration(i)*time.Second))
+	}
+
+	stats, _ := tracker.Stats("BTC-USD")
+	if stats.Samples != 3 || stats.Min != 2 || stats.Max != 4 {
+		t.Errorf("stats = %+v, want the last 3 samples", stats)
+	}
+}
+
+func TestSpreadTracker_SortedSpreadsFollowTrim(t *testing.T) {
+	tracker := NewSpreadTracker(Config{Window: time.Minute, MaxSamples: 50})
+	for i := int64(0); i < 500; i++ {
+		tracker.Add(quoteWithSpread("BTC-USD", (i*37)%23+1), t0.Add(time.Duration(i)*time.Second))
+	}
+
+	h := tracker.symbols["BTC-USD"]
+	want := make([]float64, len(h.samples))
+	for i, sample := range h.samples {
+		want[i] = sample.bps
+	}
+	slices.Sort(want)
+	if !slices.Equal(h.sorted, want) {
+		t.Errorf("sorted spreads = %v, want %v", h.sorted, want)
+	}
+}
+
+func TestSpreadTracker_TimeOfDay(t *testing.T) {
+	tracker := NewSpreadTracker(Config{})
+	tracker.Add(quoteWithSpread("BTC-USD", 10), t0.Add(9*time.Hour))
+	tracker.Add(quoteWithSpread("BTC-USD", 20), t0.Add(9*time.Hour+30*time.Minute))
+	tracker.Add(quoteWithSpread("BTC-USD", 50), t0.Add(22*time.Hour))
+
+	profile := tracker.TimeOfDay("BTC-USD")
+	if p := profile[9]; p.Hour != 9 || p.Samples != 2 || p.Mean != 15 {
+		t.Errorf("09:00 = %+v, want 2 samples with mean 15", p)
+	}
+	if p := profile[22]; p.Samples != 1 || p.Mean != 50 {
+		t.Errorf("22:00 = %+v, want 1 sample with mean 50", p)
+	}
+	if p := profile[0]; p.Samples != 0 {
+		t.Errorf("00:00 = %+v, want no samples", p)
+	}
+}
+
+func TestSpreadTracker_Blowout(t *testing.T) {
+	var blowouts []Blowout
+	tracker := NewSpreadTracker(Config{MinSamples: 5, OnBlowout: func(b Blowout) {
+		blowouts = append(blowouts, b)
+	}})
+
+	// A wide spread before MinSamples is not flagged
+	tracker.Add(quoteWithSpread("BTC-USD", 100), t0)
+	for i := range 10 {
+		tracker.Add(quoteWithSpread("BTC-USD", 10), t0.Add(time.Duration(i+1)*time.Second))
+	}
+	if len(blowouts) != 0 {
+		t.Fatalf("blowouts = %+v, want none", blowouts)
+	}
+
+	// Flagged once while it stays wide, and again after it recovers
+	for _, bps := range []int64{40, 50, 10, 35} {
+		tracker.Add(quoteWithSpread("BTC-USD", bps), t0.Add(time.Minute))
+	}
+	if len(blowouts) != 2 {
+		t.Fatalf("blowouts = %+v, want 2", blowouts)
+	}
+	if b := blowouts[0]; b.Symbol != "BTC-USD" || b.SpreadBps != 40 || b.MedianBps != 10 {
+		t.Errorf("blowout = %+v", b)
+	}
+}
+
+func TestRoundTripCost(t *testing.T) {
+	quote := quoteWithSpread("BTC-USD", 20)
+	trip := RoundTripCost(quote, models.MustParseDecimal("0.5"))
+	if !trip.Cost.Equal(models.NewDecimalFromInt(10)) || trip.CostBps != 20 {
+		t.Errorf("RoundTripCost() = %+v, want 10 at 20 bps", trip)
+	}
+
+	tracker := NewSpreadTracker(Config{})
+	for _, bps := range []int64{10, 20, 30} {
+		tracker.Add(quoteWithSpread("BTC-USD", bps), t0)
+	}
+	trip, ok := tracker.RoundTripCost("BTC-USD", models.NewDecimalFromInt(2), 50)
+	if !ok || !trip.Cost.Equal(models.NewDecimalFromInt(40)) || trip.CostBps != 20 {
+		t.Errorf("RoundTripCost() = %+v, %v, want 40 at the median 20 bps", trip, ok)
+	}
+}
+
+func TestSpreadTracker_Run(t *testing.T) {
+	tracker := NewSpreadTracker(Config{})
+
+	events := make(chan client.QuoteEvent, 3)
+	events <- client.QuoteEvent{Type: client.QuoteUpdate, Quote: quoteWithSpread("BTC-USD", 10), Received: t0}
+	events <- client.QuoteEvent{Type: client.QuoteStale, Symbol: "BTC-USD", Quote: quoteWithSpread("BTC-USD", 10)}
+	events <- client.QuoteEvent{Type: client.QuoteUpdate, Quote: quoteWithSpread("ETH-USD", 10), Received: t0}
+	close(events)
+
+	if err := tracker.Run(context.Background(), events); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if symbols := tracker.Symbols(); len(symbols) != 2 {
+		t.Errorf("Symbols() = %v, want BTC-USD and ETH-USD", symbols)
+	}
+	if stats, _ := tracker.Stats("BTC-USD"); stats.Samples != 1 {
+		t.Errorf("samples = %d, want stale events ignored", stats.Samples)
+	}
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+const defaultAgentTimeout = 5 * time.Second
+
+// AgentSigner is a Signer that delegates to a local signing agent listening
+// on a Unix socket, so the private key never enters this process.
+//
+// The agent speaks a line-delimited JSON protocol with one request per
+// connection. Requests have the form
+//
+//	{"type": "public_key", "key_id": "..."}
+//	{"type": "sign", "key_id": "...", "message": "<base64>"}
+//
+// and responses carry either "public_key" or "signature" as base64, or an
+// "error" string.
+type AgentSigner struct {
+	socketPath string
+	keyID      string
+	publicKey  ed25519.PublicKey
+
+	// Timeout bounds each exchange with the agent. Zero uses a default of
+	// five seconds.
+	Timeout time.Duration
+}
+
+type agentRequest struct {
+	Type    string `json:"type"`
+	KeyID   string `json:"key_id,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type agentResponse struct {
+	PublicKey string `json:"public_key,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// NewAgentSigner creates a signer for the key identified by keyID in the
+// agent listening at socketPath. It fetches the public key up front so that
+// an unreachable agent or unknown key is reported immediately.
+func NewAgentSigner(socketPath, keyID string) (*AgentSigner, error) {
+	s := &AgentSigner{
+		socketPath: socketPath,
+		keyID:      keyID,
+	}
+
+	resp, err := s.call(agentRequest{Type: "public_key", KeyID: keyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch public key from signing agent: %w", err)
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(resp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key from signing agent: %w", err)
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key size from signing agent: expected %d, got %d", ed25519.PublicKeySize, len(publicKey))
+	}
+	s.publicKey = ed25519.PublicKey(publicKey)
+
+	return s, nil
+}
+
+// Public implements Signer
+func (s *AgentSigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign implements Signer. Only pure Ed25519 is supported, so opts must be
+// crypto.Hash(0). The returned signature is verified against the public key
+// before it is used.
+func (s *AgentSigner) Sign(_ io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != crypto.Hash(0) {
+		return nil, fmt.Errorf("signing agent only supports pure Ed25519")
+	}
+
+	resp, err := s.call(agentRequest{
+		Type:    "sign",
+		KeyID:   s.keyID,
+		Message: base64.StdEncoding.EncodeToString(message),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("signing agent request failed: %w", err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(resp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature from signing agent: %w", err)
+	}
+	if !ed25519.Verify(s.publicKey, message, signature) {
+		return nil, fmt.Errorf("signing agent returned an invalid signature")
+	}
+
+	return signature, nil
+}
+
+// call performs a single request/response exchange with the agent
+func (s *AgentSigner) call(req agentRequest) (*agentResponse, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultAgentTimeout
+	}
+
+	conn, err := net.DialTimeout("unix", s.socketPath, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+
+	var resp agentResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("agent error: %s", resp.Error)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+// serveAgent runs a minimal signing agent holding a single key
+func serveAgent(t *testing.T, keyID string, privateKey ed25519.PrivateKey) string {
+	t.Helper()
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			var req agentRequest
+			var resp agentResponse
+			if err := json.NewDecoder(conn).Decode(&req); err != nil {
+				resp.Error = err.Error()
+			} else if req.KeyID != keyID {
+				resp.Error = "unknown key"
+			} else if req.Type == "public_key" {
+				resp.PublicKey = base64.StdEncoding.EncodeToString(privateKey.Public().(ed25519.PublicKey))
+			} else {
+				message, _ := base64.StdEncoding.DecodeString(req.Message)
+				resp.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, message))
+			}
+			json.NewEncoder(conn).Encode(resp)
+			conn.Close()
+		}
+	}()
+
+	return socketPath
+}
+
+func TestAgentSigner(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	socketPath := serveAgent(t, "trading", privateKey)
+
+	signer, err := NewAgentSigner(socketPath, "trading")
+	if err != nil {
+		t.Fatalf("NewAgentSigner() error = %v", err)
+	}
+	if !publicKey.Equal(signer.Public()) {
+		t.Error("Public() does not match agent key")
+	}
+
+	message := []byte("rh-api-key1698708981/api/v1/crypto/trading/accounts/GET")
+	signature, err := signer.Sign(nil, message, crypto.Hash(0))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if !ed25519.Verify(publicKey, message, signature) {
+		t.Error("Sign() returned an invalid signature")
+	}
+
+	// The agent signer plugs into the authenticator
+	auth, err := NewAuthenticatorWithSigner("test-api-key", signer)
+	if err != nil {
+		t.Fatalf("NewAuthenticatorWithSigner() error = %v", err)
+	}
+	if _, err := auth.GetAuthHeaders("GET", "/api/v1/crypto/trading/accounts/", ""); err != nil {
+		t.Errorf("GetAuthHeaders() error = %v", err)
+	}
+
+	if _, err := NewAgentSigner(socketPath, "unknown"); err == nil {
+		t.Error("NewAgentSigner() expected error for unknown key")
+	}
+	if _, err := NewAgentSigner(filepath.Join(t.TempDir(), "missing.sock"), "trading"); err == nil {
+		t.Error("NewAgentSigner() expected error for missing socket")
+	}
+}
+
+func TestNewAuthenticatorWithSigner(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	// ed25519.PrivateKey satisfies Signer directly
+	if _, err := NewAuthenticatorWithSigner("test-api-key", privateKey); err != nil {
+		t.Errorf("NewAuthenticatorWithSigner(ed25519.PrivateKey) error = %v", err)
+	}
+	if _, err := NewAuthenticatorWithSigner("test-api-key", nil); err == nil {
+		t.Error("NewAuthenticatorWithSigner(nil) expected error")
+	}
+}
@@ -1,46 +1,61 @@
 package auth
 
 import (
+	"crypto"
 	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
 type Authenticator struct {
-	apiKey     string
-	privateKey ed25519.PrivateKey
+	apiKey string
+	signer Signer
+
+	// clockOffset is added to the local clock when generating timestamps,
+	// stored as nanoseconds
+	clockOffset atomic.Int64
 }
 
 func NewAuthenticator(apiKey, base64PrivateKey string) (*Authenticator, error) {
-	privateKeyBytes, err := base64.StdEncoding.DecodeString(base64PrivateKey)
+	signer, err := NewMemorySigner(base64PrivateKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode private key: %w", err)
+		return nil, err
 	}
+	return NewAuthenticatorWithSigner(apiKey, signer)
+}
 
-	// Ed25519 private keys are 64 bytes (32 bytes seed + 32 bytes public key)
-	if len(privateKeyBytes) != ed25519.PrivateKeySize {
-		return nil, fmt.Errorf("invalid private key size: expected %d, got %d", ed25519.PrivateKeySize, len(privateKeyBytes))
+// NewAuthenticatorWithSigner creates an authenticator that delegates signing
+// to the given signer, so the private key never has to be held in memory
+func NewAuthenticatorWithSigner(apiKey string, signer Signer) (*Authenticator, error) {
+	if signer == nil {
+		return nil, fmt.Errorf("signer is required")
+	}
+	if _, ok := signer.Public().(ed25519.PublicKey); !ok {
+		return nil, fmt.Errorf("signer must hold an Ed25519 key, got %T", signer.Public())
 	}
-
-	privateKey := ed25519.PrivateKey(privateKeyBytes)
 
 	return &Authenticator{
-		apiKey:     apiKey,
-		privateKey: privateKey,
+		apiKey: apiKey,
+		signer: signer,
 	}, nil
 }
 
 // GetAuthHeaders generates the required authentication headers for a request
 func (a *Authenticator) GetAuthHeaders(method, path, body string) (map[string]string, error) {
-	timestamp := time.Now().Unix()
+	timestamp := a.Now().Unix()
 	
 	// Construct the message to sign: api_key + timestamp + path + method + body
 	message := a.apiKey + strconv.FormatInt(timestamp, 10) + path + method + body
 	
 	// Sign the message
-	signature := ed25519.Sign(a.privateKey, []byte(message))
+	signature, err := a.signer.Sign(rand.Reader, []byte(message), crypto.Hash(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
 	
 	// Encode signature to base64
 	encodedSignature := base64.StdEncoding.EncodeToString(signature)
@@ -54,6 +69,22 @@ func (a *Authenticator) GetAuthHeaders(method, path, body string) (map[string]st
 	return headers, nil
 }
 
+// SetClockOffset sets the offset added to the local clock when generating
+// request timestamps. It should be the server time minus the local time.
+func (a *Authenticator) SetClockOffset(offset time.Duration) {
+	a.clockOffset.Store(int64(offset))
+}
+
+// ClockOffset returns the offset added to the local clock when signing
+func (a *Authenticator) ClockOffset() time.Duration {
+	return time.Duration(a.clockOffset.Load())
+}
+
+// Now returns the current time adjusted by the clock offset
+func (a *Authenticator) Now() time.Time {
+	return time.Now().Add(a.ClockOffset())
+}
+
 // GenerateKeyPair generates a new Ed25519 key pair for API authentication
 func GenerateKeyPair() (privateKeyBase64, publicKeyBase64 string, err error) {
 	// Generate Ed25519 key pair
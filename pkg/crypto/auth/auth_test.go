@@ -3,6 +3,7 @@ package auth
 import (
 	"encoding/base64"
 	"testing"
+	"time"
 )
 
 func TestNewAuthenticator(t *testing.T) {
@@ -207,4 +208,35 @@ type parseError struct {
 
 func (e *parseError) Error() string {
 	return "invalid integer: " + e.s
-}
\ No newline at end of file
+}
+
+func TestGetAuthHeaders_ClockOffset(t *testing.T) {
+	privKey, _, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() error = %v", err)
+	}
+	auth, err := NewAuthenticator("test-api-key", privKey)
+	if err != nil {
+		t.Fatalf("NewAuthenticator() error = %v", err)
+	}
+
+	offset := time.Hour
+	auth.SetClockOffset(offset)
+	if auth.ClockOffset() != offset {
+		t.Errorf("ClockOffset() = %v, want %v", auth.ClockOffset(), offset)
+	}
+
+	headers, err := auth.GetAuthHeaders("GET", "/api/v1/crypto/trading/accounts/", "")
+	if err != nil {
+		t.Fatalf("GetAuthHeaders() error = %v", err)
+	}
+
+	timestamp, err := parseInt64(headers["x-timestamp"])
+	if err != nil {
+		t.Fatalf("x-timestamp is not a valid integer: %v", headers["x-timestamp"])
+	}
+	want := time.Now().Add(offset).Unix()
+	if timestamp < want-1 || timestamp > want+1 {
+		t.Errorf("x-timestamp = %d, want about %d", timestamp, want)
+	}
+}
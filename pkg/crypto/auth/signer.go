@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+// Signer produces Ed25519 signatures over request messages. Its method set
+// matches crypto.Signer, so any crypto.Signer holding an Ed25519 key (including
+// ed25519.PrivateKey itself) can be used, as can keys held in an HSM, KMS or
+// signing agent.
+type Signer interface {
+	// Public returns the ed25519.PublicKey matching the signing key
+	Public() crypto.PublicKey
+
+	// Sign signs message directly, without pre-hashing. opts is
+	// crypto.Hash(0) for pure Ed25519.
+	Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error)
+}
+
+// MemorySigner is a Signer backed by a private key held in memory
+type MemorySigner struct {
+	privateKey ed25519.PrivateKey
+}
+
+// NewMemorySigner creates a signer from a base64 encoded 64-byte Ed25519
+// private key
+func NewMemorySigner(base64PrivateKey string) (*MemorySigner, error) {
+	privateKeyBytes, err := base64.StdEncoding.DecodeString(base64PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode private key: %w", err)
+	}
+
+	// Ed25519 private keys are 64 bytes (32 bytes seed + 32 bytes public key)
+	if len(privateKeyBytes) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key size: expected %d, got %d", ed25519.PrivateKeySize, len(privateKeyBytes))
+	}
+
+	return NewMemorySignerFromKey(ed25519.PrivateKey(privateKeyBytes)), nil
+}
+
+// NewMemorySignerFromKey creates a signer from an Ed25519 private key
+func NewMemorySignerFromKey(privateKey ed25519.PrivateKey) *MemorySigner {
+	return &MemorySigner{privateKey: privateKey}
+}
+
+// Public implements Signer
+func (s *MemorySigner) Public() crypto.PublicKey {
+	return s.privateKey.Public()
+}
+
+// Sign implements Signer
+func (s *MemorySigner) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.privateKey.Sign(rand, message, opts)
+}
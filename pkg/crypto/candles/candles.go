@@ -0,0 +1,396 @@
+package candles
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/client"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// Interval limits
+const (
+	MinInterval = time.Second
+	MaxInterval = 24 * time.Hour
+)
+
+// Source is the quote price a bar is built from
+type Source string
+
+const (
+	// SourceMid is halfway between the bid and the ask
+	SourceMid Source = "mid"
+	// SourceBid is the bid inclusive of the sell spread
+	SourceBid Source = "bid"
+	// SourceAsk is the ask inclusive of the buy spread
+	SourceAsk Source = "ask"
+)
+
+// half is used to take the midpoint of two prices exactly
+var half = models.NewDecimal(5, -1)
+
+func (s Source) valid() bool {
+	return s == SourceMid || s == SourceBid || s == SourceAsk
+}
+
+// price returns the source's price from a quote, and false if the quote does
+// not have one
+func (s Source) price(quote models.BestBidAskResult) (models.Decimal, bool) {
+	bid := quote.BidInclusiveOfSellSpread
+	ask := quote.AskInclusiveOfBuySpread
+	switch s {
+	case SourceBid:
+		return bid, !bid.IsZero()
+	case SourceAsk:
+		return ask, !ask.IsZero()
+	case SourceMid:
+		if bid.IsZero() || ask.IsZero() {
+			return models.Decimal{}, false
+		}
+		return bid.Add(ask).Mul(half), true
+	}
+	return models.Decimal{}, false
+}
+
+// Bar is an OHLC bar for one symbol, source and interval
+type Bar struct {
+	Symbol   string        `json:"symbol"`
+	Source   Source        `json:"source"`
+	Interval time.Duration `json:"-"`
+	Start    time.Time     `json:"start"`
+
+	Open  models.Decimal `json:"open"`
+	High  models.Decimal `json:"high"`
+	Low   models.Decimal `json:"low"`
+	Close models.Decimal `json:"close"`
+
+	// Ticks is the number of quotes aggregated into the bar. Quotes carry
+	// no traded volume, so this is the only activity measure available. It
+	// is 0 for bars filled across a gap.
+	Ticks int `json:"ticks"`
+}
+
+// End returns the end of the bar's interval. The last bar of a day ends at
+// midnight UTC even when the interval does not divide 24h.
+func (b Bar) End() time.Time {
+	end := b.Start.Add(b.Interval)
+	if midnight := utcMidnight(b.Start).Add(24 * time.Hour); end.After(midnight) {
+		return midnight
+	}
+	return end
+}
+
+// Filled reports whether the bar was filled across a gap in quotes
+func (b Bar) Filled() bool {
+	return b.Ticks == 0
+}
+
+// MarshalJSON encodes the interval as a duration string such as "1m0s"
+func (b Bar) MarshalJSON() ([]byte, error) {
+	type Alias Bar
+	return json.Marshal(struct {
+		Alias
+		Interval string `json:"interval"`
+	}{
+		Alias:    Alias(b),
+		Interval: b.Interval.String(),
+	})
+}
+
+// UnmarshalJSON decodes a bar written by MarshalJSON
+func (b *Bar) UnmarshalJSON(data []byte) error {
+	type Alias Bar
+	aux := struct {
+		*Alias
+		Interval string `json:"interval"`
+	}{Alias: (*Alias)(b)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	interval, err := time.ParseDuration(aux.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid bar interval: %w", err)
+	}
+	b.Interval = interval
+	return nil
+}
+
+// Config configures an Aggregator
+type Config struct {
+	// Interval is the length of each bar, from 1s to 24h. Bars are aligned
+	// to multiples of the interval since midnight UTC, so when the interval
+	// does not divide 24h the last bar of each day is shorter.
+	Interval time.Duration
+
+	// Sources are the prices bars are built from. Defaults to mid, bid and
+	// ask.
+	Sources []Source
+
+	// FillGaps emits a flat bar at the previous close for each interval
+	// without quotes, until the symbol goes stale. Otherwise such intervals
+	// have no bar.
+	//
+	// A QuoteStream only delivers quotes that change, so gaps usually mean
+	// the price did not move and filling them gives a continuous series.
+	FillGaps bool
+}
+
+// Aggregator builds bars from quotes. It is safe for concurrent use.
+type Aggregator struct {
+	config Config
+
+	mu     sync.Mutex
+	series map[seriesKey]*series
+}
+
+type seriesKey struct {
+	symbol string
+	source Source
+}
+
+// series is the bar in progress for a symbol and source
+type series struct {
+	current *Bar
+
+	// last is the most recent completed bar, the basis for filling gaps
+	last *Bar
+
+	// live is false once the symbol has gone stale, which stops gap filling
+	live bool
+}
+
+// NewAggregator creates an aggregator
+func NewAggregator(config Config) (*Aggregator, error) {
+	if config.Interval < MinInterval || config.Interval > MaxInterval {
+		return nil, fmt.Errorf("invalid candle interval %s: must be between %s and %s", config.Interval, MinInterval, MaxInterval)
+	}
+	if len(config.Sources) == 0 {
+		config.Sources = []Source{SourceMid, SourceBid, SourceAsk}
+	}
+	for _, source := range config.Sources {
+		if !source.valid() {
+			return nil, fmt.Errorf("invalid candle source %q", source)
+		}
+	}
+	config.Sources = slices.Clone(config.Sources)
+
+	return &Aggregator{
+		config: config,
+		series: make(map[seriesKey]*series),
+	}, nil
+}
+
+// Interval returns the bar interval
+func (a *Aggregator) Interval() time.Duration {
+	return a.config.Interval
+}
+
+// Add aggregates a quote received at the given time and returns any bars
+// it completes. Quotes for intervals that already have a bar are ignored.
+func (a *Aggregator) Add(quote models.BestBidAskResult, at time.Time) []Bar {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	symbol := strings.ToUpper(quote.Symbol)
+	start := a.barStart(at)
+
+	var completed []Bar
+	for _, source := range a.config.Sources {
+		price, ok := source.price(quote)
+		if !ok {
+			continue
+		}
+
+		s := a.seriesFor(symbol, source)
+		if s.current != nil && start.Before(s.current.Start) {
+			continue
+		}
+		if s.current == nil && s.last != nil && start.Before(s.last.End()) {
+			continue
+		}
+		if s.current != nil && start.After(s.current.Start) {
+			completed = a.complete(s, completed)
+		}
+
+		if s.current == nil {
+			completed = a.fill(s, start, completed)
+			s.live = true
+			s.current = &Bar{
+				Symbol:   symbol,
+				Source:   source,
+				Interval: a.config.Interval,
+				Start:    start,
+				Open:     price,
+				High:     price,
+				Low:      price,
+			}
+		}
+		bar := s.current
+		if price.GreaterThan(bar.High) {
+			bar.High = price
+		}
+		if price.LessThan(bar.Low) {
+			bar.Low = price
+		}
+		bar.Close = price
+		bar.Ticks++
+	}
+	return completed
+}
+
+// Flush completes bars whose interval ended at or before now and, with
+// FillGaps, fills intervals since then. Call it periodically so bars are
+// emitted even when no quotes arrive.
+func (a *Aggregator) Flush(now time.Time) []Bar {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	start := a.barStart(now)
+	var completed []Bar
+	for _, key := range a.keys() {
+		s := a.series[key]
+		if s.current != nil && !s.current.End().After(now) {
+			completed = a.complete(s, completed)
+		}
+		if s.current == nil {
+			completed = a.fill(s, start, completed)
+		}
+	}
+	return completed
+}
+
+// Stale stops gap filling for symbol until its next quote
+func (a *Aggregator) Stale(symbol string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	symbol = strings.ToUpper(symbol)
+	for key, s := range a.series {
+		if key.symbol == symbol {
+			s.live = false
+		}
+	}
+}
+
+// Run aggregates quote events until events is closed or ctx is done, and
+// passes completed bars to handle. Bars are flushed at each interval
+// boundary. It returns the first error from handle.
+func (a *Aggregator) Run(ctx context.Context, events <-chan client.QuoteEvent, handle func(Bar) error) error {
+	emit := func(bars []Bar) error {
+		for _, bar := range bars {
+			if err := handle(bar); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	timer := time.NewTimer(a.untilBoundary(time.Now()))
+	defer timer.Stop()
+	for {
+		var bars []Bar
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			switch event.Type {
+			case client.QuoteUpdate:
+				bars = a.Add(event.Quote, event.Received)
+			case client.QuoteStale:
+				a.Stale(event.Symbol)
+			}
+		case now := <-timer.C:
+			bars = a.Flush(now)
+			timer.Reset(a.untilBoundary(time.Now()))
+		}
+
+		if err := emit(bars); err != nil {
+			return err
+		}
+	}
+}
+
+// complete moves the bar in progress to the completed bars
+func (a *Aggregator) complete(s *series, completed []Bar) []Bar {
+	completed = append(completed, *s.current)
+	s.last = s.current
+	s.current = nil
+	return completed
+}
+
+// fill appends flat bars for the intervals between the last completed bar
+// and start
+func (a *Aggregator) fill(s *series, start time.Time, completed []Bar) []Bar {
+	if !a.config.FillGaps || !s.live || s.last == nil {
+		return completed
+	}
+	for next := s.last.End(); next.Before(start); next = s.last.End() {
+		price := s.last.Close
+		bar := &Bar{
+			Symbol:   s.last.Symbol,
+			Source:   s.last.Source,
+			Interval: a.config.Interval,
+			Start:    next,
+			Open:     price,
+			High:     price,
+			Low:      price,
+			Close:    price,
+		}
+		completed = append(completed, *bar)
+		s.last = bar
+	}
+	return completed
+}
+
+// seriesFor returns the series for symbol and source, creating it if needed
+func (a *Aggregator) seriesFor(symbol string, source Source) *series {
+	key := seriesKey{symbol: symbol, source: source}
+	s, ok := a.series[key]
+	if !ok {
+		s = &series{}
+		a.series[key] = s
+	}
+	return s
+}
+
+// keys returns the series keys in a stable order
+func (a *Aggregator) keys() []seriesKey {
+	keys := make([]seriesKey, 0, len(a.series))
+	for key := range a.series {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(x, y seriesKey) int {
+		if c := strings.Compare(x.symbol, y.symbol); c != 0 {
+			return c
+		}
+		return strings.Compare(string(x.source), string(y.source))
+	})
+	return keys
+}
+
+// barStart returns the start of the interval containing t
+func (a *Aggregator) barStart(t time.Time) time.Time {
+	midnight := utcMidnight(t)
+	return midnight.Add(t.Sub(midnight).Truncate(a.config.Interval))
+}
+
+// untilBoundary returns the time from now to the next bar boundary
+func (a *Aggregator) untilBoundary(now time.Time) time.Duration {
+	bar := Bar{Interval: a.config.Interval, Start: a.barStart(now)}
+	return bar.End().Sub(now)
+}
+
+// utcMidnight returns the start of the UTC day containing t
+func utcMidnight(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
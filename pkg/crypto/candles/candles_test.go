@@ -0,0 +1,213 @@
+package candles
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/client"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+var t0 = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func quote(symbol, bid, ask string) models.BestBidAskResult {
+	return models.BestBidAskResult{
+		Symbol:                   symbol,
+		BidInclusiveOfSellSpread: models.MustParseDecimal(bid),
+		AskInclusiveOfBuySpread:  models.MustParseDecimal(ask),
+	}
+}
+
+func newAggregator(t *testing.T, config Config) *Aggregator {
+	t.Helper()
+	a, err := NewAggregator(config)
+	if err != nil {
+		t.Fatalf("NewAggregator() error = %v", err)
+	}
+	return a
+}
+
+func ohlc(bar Bar) string {
+	return bar.Open.String() + " " + bar.High.String() + " " + bar.Low.String() + " " + bar.Close.String()
+}
+
+func TestNewAggregator_Validation(t *testing.T) {
+	for _, config := range []Config{
+		{Interval: 500 * time.Millisecond},
+		{Interval: 48 * time.Hour},
+		{Interval: time.Minute, Sources: []Source{"last"}},
+	} {
+		if _, err := NewAggregator(config); err == nil {
+			t.Errorf("NewAggregator(%+v) succeeded, want error", config)
+		}
+	}
+}
+
+func TestAggregator_OHLC(t *testing.T) {
+	a := newAggregator(t, Config{Interval: time.Minute})
+
+	a.Add(quote("btc-usd", "100", "102"), t0.Add(5*time.Second))
+	a.Add(quote("BTC-USD", "104", "106"), t0.Add(20*time.Second))
+	a.Add(quote("BTC-USD", "98", "99"), t0.Add(40*time.Second))
+	a.Add(quote("BTC-USD", "101", "102"), t0.Add(59*time.Second))
+
+	if bars := a.Flush(t0.Add(59 * time.Second)); len(bars) != 0 {
+		t.Fatalf("Flush() before the interval ended returned %d bars", len(bars))
+	}
+
+	bars := a.Add(quote("BTC-USD", "110", "112"), t0.Add(61*time.Second))
+	if len(bars) != 3 {
+		t.Fatalf("Add() completed %d bars, want 3", len(bars))
+	}
+
+	want := map[Source]string{
+		SourceMid: "101.0 105.0 98.5 101.5",
+		SourceBid: "100 104 98 101",
+		SourceAsk: "102 106 99 102",
+	}
+	for _, bar := range bars {
+		if bar.Symbol != "BTC-USD" || !bar.Start.Equal(t0) || bar.Ticks != 4 {
+			t.Errorf("bar = %+v", bar)
+		}
+		if got := ohlc(bar); got != want[bar.Source] {
+			t.Errorf("%s OHLC = %s, want %s", bar.Source, got, want[bar.Source])
+		}
+	}
+}
+
+func TestAggregator_Gaps(t *testing.T) {
+	a := newAggregator(t, Config{Interval: time.Minute, Sources: []Source{SourceBid}, FillGaps: true})
+
+	a.Add(quote("BTC-USD", "100", "101"), t0)
+	bars := a.Add(quote("BTC-USD", "105", "106"), t0.Add(3*time.Minute+time.Second))
+	if len(bars) != 3 {
+		t.Fatalf("Add() completed %d bars, want 3", len(bars))
+	}
+	for i, bar := range bars[1:] {
+		if !bar.Filled() || ohlc(bar) != "100 100 100 100" || !bar.Start.Equal(t0.Add(time.Duration(i+1)*time.Minute)) {
+			t.Errorf("gap bar %d = %+v", i, bar)
+		}
+	}
+
+	// Flush fills intervals with no quotes at all
+	bars = a.Flush(t0.Add(6 * time.Minute))
+	if len(bars) != 3 || bars[0].Ticks != 1 || !bars[2].Filled() || !bars[2].Start.Equal(t0.Add(5*time.Minute)) {
+		t.Errorf("Flush() = %+v", bars)
+	}
+
+	// A stale symbol is not filled
+	a.Stale("BTC-USD")
+	if bars := a.Flush(t0.Add(10 * time.Minute)); len(bars) != 0 {
+		t.Errorf("Flush() after Stale returned %d bars, want 0", len(bars))
+	}
+	bars = a.Add(quote("BTC-USD", "90", "91"), t0.Add(12*time.Minute))
+	if len(bars) != 0 {
+		t.Errorf("Add() after Stale completed %d bars, want 0", len(bars))
+	}
+}
+
+func TestAggregator_WithoutFillGaps(t *testing.T) {
+	a := newAggregator(t, Config{Interval: time.Minute, Sources: []Source{SourceMid}})
+
+	a.Add(quote("BTC-USD", "100", "101"), t0)
+	bars := a.Add(quote("BTC-USD", "105", "106"), t0.Add(5*time.Minute))
+	if len(bars) != 1 {
+		t.Errorf("Add() completed %d bars, want 1", len(bars))
+	}
+
+	// A late quote for a completed interval is ignored
+	if bars := a.Add(quote("BTC-USD", "1", "2"), t0.Add(30*time.Second)); len(bars) != 0 {
+		t.Errorf("late quote completed %d bars", len(bars))
+	}
+	bars = a.Flush(t0.Add(6 * time.Minute))
+	if len(bars) != 1 || ohlc(bars[0]) != "105.5 105.5 105.5 105.5" {
+		t.Errorf("Flush() = %+v", bars)
+	}
+}
+
+func TestAggregator_DailyBarsAlignToMidnight(t *testing.T) {
+	a := newAggregator(t, Config{Interval: MaxInterval, Sources: []Source{SourceAsk}})
+
+	a.Add(quote("ETH-USD", "3000", "3001"), t0)
+	bars := a.Flush(t0.Add(12 * time.Hour))
+	if len(bars) != 1 || !bars[0].Start.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Flush() = %+v, want one bar starting at midnight", bars)
+	}
+}
+
+func TestAggregator_UnevenIntervalsAlignToMidnight(t *testing.T) {
+	a := newAggregator(t, Config{Interval: 7 * time.Hour, Sources: []Source{SourceAsk}, FillGaps: true})
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	// 21:00 starts a three hour bar that ends at midnight
+	a.Add(quote("ETH-USD", "3000", "3001"), day.Add(-2*time.Hour))
+	bars := a.Add(quote("ETH-USD", "3000", "3002"), day.Add(30*time.Minute))
+	if len(bars) != 1 || !bars[0].Start.Equal(day.Add(-3*time.Hour)) || !bars[0].End().Equal(day) {
+		t.Fatalf("Add() = %+v, want the bar from 21:00 to midnight", bars)
+	}
+
+	// 00:30 is in the bar starting at midnight, and filled bars follow the
+	// same boundaries
+	bars = a.Flush(day.Add(15 * time.Hour))
+	if len(bars) != 2 || !bars[0].Start.Equal(day) || !bars[1].Start.Equal(day.Add(7*time.Hour)) || bars[1].Ticks != 0 {
+		t.Errorf("Flush() = %+v, want bars from 00:00 and 07:00", bars)
+	}
+	if got := a.untilBoundary(day.Add(22 * time.Hour)); got != 2*time.Hour {
+		t.Errorf("untilBoundary() at 22:00 = %v, want 2h", got)
+	}
+}
+
+func TestAggregator_Run(t *testing.T) {
+	a := newAggregator(t, Config{Interval: time.Second, Sources: []Source{SourceBid}})
+
+	events := make(chan client.QuoteEvent, 4)
+	events <- client.QuoteEvent{Type: client.QuoteUpdate, Quote: quote("BTC-USD", "100", "101"), Received: t0}
+	events <- client.QuoteEvent{Type: client.QuoteError}
+	events <- client.QuoteEvent{Type: client.QuoteUpdate, Quote: quote("BTC-USD", "101", "102"), Received: t0.Add(time.Second)}
+	close(events)
+
+	var bars []Bar
+	err := a.Run(context.Background(), events, func(bar Bar) error {
+		bars = append(bars, bar)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(bars) != 1 || ohlc(bars[0]) != "100 100 100 100" {
+		t.Errorf("bars = %+v", bars)
+	}
+}
+
+func TestBar_JSON(t *testing.T) {
+	bar := Bar{
+		Symbol:   "BTC-USD",
+		Source:   SourceMid,
+		Interval: 5 * time.Minute,
+		Start:    t0,
+		Open:     models.MustParseDecimal("45000.5"),
+		High:     models.MustParseDecimal("45100"),
+		Low:      models.MustParseDecimal("44900.25"),
+		Close:    models.MustParseDecimal("45050"),
+		Ticks:    12,
+	}
+
+	data, err := json.Marshal(bar)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"symbol":"BTC-USD","source":"mid","start":"2024-01-01T12:00:00Z","open":"45000.5","high":"45100","low":"44900.25","close":"45050","ticks":12,"interval":"5m0s"}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	var decoded Bar
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded.Interval != bar.Interval || !decoded.Start.Equal(bar.Start) || !decoded.Low.Equal(bar.Low) {
+		t.Errorf("decoded = %+v, want %+v", decoded, bar)
+	}
+}
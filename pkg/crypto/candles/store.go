@@ -0,0 +1,184 @@
+package candles
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store appends completed bars to a local file, one JSON object per line.
+// It is safe for concurrent use.
+type Store struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenStore opens or creates a bar file for appending. A partial last line,
+// left by a write that was interrupted, is removed.
+func OpenStore(path string) (*Store, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open candle store: %w", err)
+	}
+
+	if err := truncatePartialLine(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to recover candle store: %w", err)
+	}
+
+	return &Store{file: file}, nil
+}
+
+// truncatePartialLine cuts the file after its last newline
+func truncatePartialLine(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	end := info.Size()
+	buf := make([]byte, 4096)
+	for offset := end; offset > 0; {
+		n := min(int64(len(buf)), offset)
+		offset -= n
+		if _, err := file.ReadAt(buf[:n], offset); err != nil {
+			return err
+		}
+
+		i := bytes.LastIndexByte(buf[:n], '\n')
+		if i >= 0 {
+			if newEnd := offset + int64(i) + 1; newEnd != end {
+				return file.Truncate(newEnd)
+			}
+			return nil
+		}
+	}
+
+	// No complete line at all
+	if end > 0 {
+		return file.Truncate(0)
+	}
+	return nil
+}
+
+// Append writes bars to the end of the file in a single write
+func (s *Store) Append(bars ...Bar) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, bar := range bars {
+		if err := enc.Encode(bar); err != nil {
+			return fmt.Errorf("failed to encode bar: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to append bars: %w", err)
+	}
+	return nil
+}
+
+// Sync commits written bars to stable storage
+func (s *Store) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Sync()
+}
+
+// Close syncs and closes the file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+// Filter selects bars when replaying. Zero fields match every bar.
+type Filter struct {
+	Symbol   string
+	Source   Source
+	Interval time.Duration
+
+	// From and To limit bar start times to [From, To)
+	From time.Time
+	To   time.Time
+}
+
+// Match reports whether bar passes the filter
+func (f Filter) Match(bar Bar) bool {
+	switch {
+	case f.Symbol != "" && !strings.EqualFold(f.Symbol, bar.Symbol):
+		return false
+	case f.Source != "" && f.Source != bar.Source:
+		return false
+	case f.Interval != 0 && f.Interval != bar.Interval:
+		return false
+	case !f.From.IsZero() && bar.Start.Before(f.From):
+		return false
+	case !f.To.IsZero() && !bar.Start.Before(f.To):
+		return false
+	}
+	return true
+}
+
+// Replay returns an iterator over the bars stored in r that match filter, in
+// the order they were written. A partial last line, as left by an
+// interrupted or concurrent write, is skipped. A malformed line is yielded
+// as an error and ends the loop.
+func Replay(r io.Reader, filter Filter) iter.Seq2[Bar, error] {
+	return func(yield func(Bar, error) bool) {
+		reader := bufio.NewReader(r)
+		for line := 1; ; line++ {
+			data, err := reader.ReadBytes('\n')
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(Bar{}, fmt.Errorf("failed to read bars: %w", err))
+				return
+			}
+
+			var bar Bar
+			if err := json.Unmarshal(data, &bar); err != nil {
+				yield(Bar{}, fmt.Errorf("invalid bar on line %d: %w", line, err))
+				return
+			}
+			if filter.Match(bar) && !yield(bar, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ReplayFile returns an iterator over the bars stored in the file at path
+// that match filter. See Replay.
+func ReplayFile(path string, filter Filter) iter.Seq2[Bar, error] {
+	return func(yield func(Bar, error) bool) {
+		file, err := os.Open(path)
+		if err != nil {
+			yield(Bar{}, fmt.Errorf("failed to open candle store: %w", err))
+			return
+		}
+		defer file.Close()
+
+		for bar, err := range Replay(file, filter) {
+			if !yield(bar, err) {
+				return
+			}
+		}
+	}
+}
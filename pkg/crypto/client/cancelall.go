@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// Defaults for CancelAllConfig
+const (
+	defaultCancelConcurrency    = 4
+	defaultCancelConfirmTimeout = 30 * time.Second
+)
+
+// CancelAllConfig configures CancelAll. Zero fields use defaults.
+type CancelAllConfig struct {
+	// Concurrency is the most cancel requests in flight at once. Every
+	// request still waits for the client's rate limiter. Defaults to 4.
+	Concurrency int
+
+	// Confirm waits after canceling until each order is seen to have left
+	// the open state
+	Confirm bool
+
+	// ConfirmTimeout bounds the wait for confirmation. Defaults to 30s.
+	ConfirmTimeout time.Duration
+}
+
+// CancelResult is the outcome of canceling one order
+type CancelResult struct {
+	// Order is the order as found, or as last seen when confirming
+	Order models.Order
+
+	// Err is set if the cancel request failed
+	Err error
+
+	// Confirmed is set when the order was seen to leave the open state.
+	// Order.State then tells whether it was canceled or filled first.
+	Confirmed bool
+}
+
+// CancelReport lists the outcome for every order CancelAll tried to cancel
+type CancelReport struct {
+	Results []CancelResult
+
+	// confirm records whether confirmation was requested
+	confirm bool
+}
+
+// Canceled returns the number of orders whose cancel request succeeded
+func (r *CancelReport) Canceled() int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Err joins the errors of failed cancel requests, and reports orders that
+// were not confirmed if confirmation was requested. It returns nil if
+// every order was canceled.
+func (r *CancelReport) Err() error {
+	var errs []error
+	for _, result := range r.Results {
+		switch {
+		case result.Err != nil:
+			errs = append(errs, fmt.Errorf("order %s: %w", result.Order.ID, result.Err))
+		case r.confirm && !result.Confirmed:
+			errs = append(errs, fmt.Errorf("order %s: cancel not confirmed", result.Order.ID))
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// CancelAll cancels every open order matching filter, which can narrow
+// orders by symbol, side, type and, through CreatedAtEnd, age. Unless the
+// filter names a state, both open and partially filled orders are
+// canceled. Orders are canceled concurrently, and the outcome for each is
+// in the report. The error is only set if the orders could not be listed.
+func (s *TradingService) CancelAll(ctx context.Context, filter *models.OrdersFilter, config CancelAllConfig) (*CancelReport, error) {
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaultCancelConcurrency
+	}
+	if config.ConfirmTimeout <= 0 {
+		config.ConfirmTimeout = defaultCancelConfirmTimeout
+	}
+
+	orders, err := s.cancelableOrders(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CancelReport{Results: make([]CancelResult, len(orders)), confirm: config.Confirm}
+	sem := make(chan struct{}, config.Concurrency)
+	var wg sync.WaitGroup
+	for i, order := range orders {
+		result := &report.Results[i]
+		result.Order = order
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+			result.Err = s.CancelOrder(ctx, order.ID)
+		}()
+	}
+	wg.Wait()
+
+	if config.Confirm {
+		s.confirmCanceled(ctx, report, config.ConfirmTimeout)
+	}
+	return report, nil
+}
+
+// cancelableOrders lists the orders matching filter that are not in a
+// terminal state
+func (s *TradingService) cancelableOrders(ctx context.Context, filter *models.OrdersFilter) ([]models.Order, error) {
+	base := models.OrdersFilter{}
+	if filter != nil {
+		base = *filter
+	}
+	base.Cursor = ""
+
+	states := []models.OrderState{models.OrderStateOpen, models.OrderStatePartiallyFilled}
+	if base.State != "" {
+		states = []models.OrderState{base.State}
+	}
+
+	// An order can move between states while they are listed in turn
+	seen := make(map[string]bool)
+	var orders []models.Order
+	for _, state := range states {
+		stateFilter := base
+		stateFilter.State = state
+		for order, err := range s.NewOrdersPaginator(&stateFilter).All(ctx) {
+			if err != nil {
+				return nil, err
+			}
+			if !seen[order.ID] && !order.State.IsTerminal() {
+				seen[order.ID] = true
+				orders = append(orders, order)
+			}
+		}
+	}
+	return orders, nil
+}
+
+// confirmCanceled watches the orders whose cancel request succeeded until
+// each reaches a terminal state or the timeout passes
+func (s *TradingService) confirmCanceled(ctx context.Context, report *CancelReport, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	watcher := s.NewOrderWatcher(OrderWatcherConfig{})
+	go watcher.Run(ctx)
+
+	for i := range report.Results {
+		if report.Results[i].Err == nil {
+			watcher.Watch(&report.Results[i].Order)
+		}
+	}
+
+	// The watcher polls for every order at once, so waiting on them in
+	// turn takes no longer than waiting on the slowest
+	for i := range report.Results {
+		result := &report.Results[i]
+		if result.Err != nil {
+			continue
+		}
+		order, err := watcher.WaitForState(ctx, result.Order.ID,
+			models.OrderStateCanceled, models.OrderStateFilled, models.OrderStateFailed)
+		if err != nil {
+			continue
+		}
+		result.Order = *order
+		result.Confirmed = true
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+func TestCancelAll(t *testing.T) {
+	now := time.Now()
+	order := func(id, symbol string, state models.OrderState) models.Order {
+		return models.Order{ID: id, Symbol: symbol, State: state, UpdatedAt: now}
+	}
+	server := &orderServer{
+		orders: map[string]models.Order{
+			"open":    order("open", "BTC-USD", models.OrderStateOpen),
+			"partial": order("partial", "BTC-USD", models.OrderStatePartiallyFilled),
+			"stuck":   order("stuck", "BTC-USD", models.OrderStateOpen),
+			"filled":  order("filled", "BTC-USD", models.OrderStateFilled),
+			"eth":     order("eth", "ETH-USD", models.OrderStateOpen),
+		},
+		uncancelable: map[string]bool{"stuck": true},
+	}
+	c := newTestClient(t, server.handle)
+
+	report, err := c.Trading.CancelAll(context.Background(), &models.OrdersFilter{Symbol: "BTC-USD"}, CancelAllConfig{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("CancelAll() error = %v", err)
+	}
+
+	slices.Sort(server.cancels)
+	if want := []string{"open", "partial", "stuck"}; !slices.Equal(server.cancels, want) {
+		t.Errorf("canceled = %v, want %v", server.cancels, want)
+	}
+	if len(report.Results) != 3 || report.Canceled() != 2 {
+		t.Errorf("results = %+v, want 2 of 3 canceled", report.Results)
+	}
+	for _, result := range report.Results {
+		if (result.Order.ID == "stuck") != (result.Err != nil) {
+			t.Errorf("result for %s error = %v", result.Order.ID, result.Err)
+		}
+	}
+	if err := report.Err(); !stderrors.Is(err, errors.ErrValidation) {
+		t.Errorf("Err() = %v, want the failed cancel", err)
+	}
+}
+
+func TestCancelAll_Confirm(t *testing.T) {
+	server := &orderServer{orders: map[string]models.Order{
+		"order-1": {ID: "order-1", State: models.OrderStateOpen, UpdatedAt: time.Now()},
+		"order-2": {ID: "order-2", State: models.OrderStateOpen, UpdatedAt: time.Now()},
+	}}
+	c := newTestClient(t, server.handle)
+
+	report, err := c.Trading.CancelAll(context.Background(), nil, CancelAllConfig{Confirm: true, ConfirmTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("CancelAll() error = %v", err)
+	}
+	if err := report.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	for _, result := range report.Results {
+		if !result.Confirmed || result.Order.State != models.OrderStateCanceled {
+			t.Errorf("result = %+v, want confirmed canceled", result)
+		}
+	}
+}
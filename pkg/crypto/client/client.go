@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/auth"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/credentials"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/ratelimit"
@@ -20,8 +21,6 @@ import (
 const (
 	defaultBaseURL = "https://trading.robinhood.com"
 	defaultTimeout = 30 * time.Second
-	maxRetries     = 3
-	retryDelay     = time.Second
 )
 
 // Client is the main client for interacting with the Robinhood Crypto API
@@ -30,11 +29,31 @@ type Client struct {
 	baseURL       string
 	auth          *auth.Authenticator
 	rateLimiter   *ratelimit.RateLimiter
+	retryPolicy   RetryPolicy
+
+	// attemptObserver is notified after every request attempt
+	attemptObserver func(Attempt)
+	middleware      []Middleware
+
+	// skew tracks the server clock offset measured from Date headers
+	skew           clockSkew
+	compensateSkew bool
+
+	// quantize rounds orders to the trading pair's increments before
+	// placing them, see InstrumentRegistry
+	quantize     bool
+	roundingMode models.RoundingMode
+
+	// quoteCacheTTL enables the quote cache, see QuoteCache
+	quoteCacheTTL time.Duration
 	
 	// Service clients
 	Account    *AccountService
 	MarketData *MarketDataService
 	Trading    *TradingService
+
+	// Instruments caches trading pair details used to quantize orders
+	Instruments *InstrumentRegistry
 }
 
 // Option is a functional option for configuring the client
@@ -61,18 +80,94 @@ func WithRateLimiter(rl *ratelimit.RateLimiter) Option {
 	}
 }
 
+// WithRetryPolicy sets the policy used to retry failed requests. A nil
+// policy disables retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithAttemptObserver sets a function that is called after every request
+// attempt, including the retry decision taken for it
+func WithAttemptObserver(observer func(Attempt)) Option {
+	return func(c *Client) {
+		c.attemptObserver = observer
+	}
+}
+
+// WithClockSkewCompensation sets whether request timestamps are adjusted by
+// the clock skew measured from server responses. It is enabled by default.
+func WithClockSkewCompensation(enabled bool) Option {
+	return func(c *Client) {
+		c.compensateSkew = enabled
+	}
+}
+
+// WithOrderQuantization sets whether PlaceOrder rounds quantities and prices
+// to the trading pair's increments and checks order sizes locally. It is
+// enabled by default.
+func WithOrderQuantization(enabled bool) Option {
+	return func(c *Client) {
+		c.quantize = enabled
+	}
+}
+
+// WithRoundingMode sets how PlaceOrder rounds quantities and prices to the
+// trading pair's increments. The default is models.RoundDown.
+func WithRoundingMode(mode models.RoundingMode) Option {
+	return func(c *Client) {
+		c.roundingMode = mode
+	}
+}
+
+// WithQuoteCache caches best bid/ask quotes for ttl per symbol and
+// coalesces concurrent GetBestBidAsk calls for the same symbols into one
+// request. It is disabled by default.
+func WithQuoteCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.quoteCacheTTL = ttl
+	}
+}
+
 // New creates a new Robinhood Crypto API client
 func New(apiKey, privateKey string, opts ...Option) (*Client, error) {
 	authenticator, err := auth.NewAuthenticator(apiKey, privateKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authenticator: %w", err)
 	}
+	return newClient(authenticator, opts), nil
+}
 
+// NewWithSigner creates a new Robinhood Crypto API client that delegates
+// request signing to signer, such as an auth.AgentSigner or a key held in
+// an HSM or KMS
+func NewWithSigner(apiKey string, signer auth.Signer, opts ...Option) (*Client, error) {
+	authenticator, err := auth.NewAuthenticatorWithSigner(apiKey, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authenticator: %w", err)
+	}
+	return newClient(authenticator, opts), nil
+}
+
+// NewFromCredentials creates a new Robinhood Crypto API client from
+// credentials resolved by the credentials package
+func NewFromCredentials(creds *credentials.Credentials, opts ...Option) (*Client, error) {
+	return NewWithSigner(creds.APIKey, creds.Signer(), opts...)
+}
+
+// newClient applies options and initializes the service clients
+func newClient(authenticator *auth.Authenticator, opts []Option) *Client {
 	c := &Client{
 		httpClient:  &http.Client{Timeout: defaultTimeout},
 		baseURL:     defaultBaseURL,
 		auth:        authenticator,
 		rateLimiter: ratelimit.DefaultRateLimiter(),
+		retryPolicy: DefaultRetryPolicy(),
+
+		compensateSkew: true,
+		quantize:       true,
+		roundingMode:   models.RoundDown,
 	}
 
 	// Apply options
@@ -84,127 +179,241 @@ func New(apiKey, privateKey string, opts ...Option) (*Client, error) {
 	c.Account = &AccountService{client: c}
 	c.MarketData = &MarketDataService{client: c}
 	c.Trading = &TradingService{client: c}
+	c.Instruments = newInstrumentRegistry(c.Trading)
+	if c.quoteCacheTTL > 0 {
+		c.MarketData.Cache = newQuoteCache(c.MarketData, c.quoteCacheTTL)
+	}
 
-	return c, nil
+	return c
 }
 
-// request performs an HTTP request with authentication and rate limiting
-func (c *Client) request(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Response, error) {
+// request performs an HTTP request with authentication, rate limiting and
+// retries. Every attempt rebuilds the body and re-signs the request, and
+// attempts whose outcome is ambiguous are only resent when the request is
+// safe to repeat.
+func (c *Client) request(ctx context.Context, req *apiRequest) (*apiResponse, error) {
 	// Build URL
-	u, err := url.Parse(c.baseURL + path)
+	u, err := url.Parse(c.baseURL + req.path)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
-	if query != nil {
-		u.RawQuery = query.Encode()
+	if req.query != nil {
+		u.RawQuery = req.query.Encode()
 	}
 
-	// Prepare body
-	var bodyReader io.Reader
-	var bodyStr string
-	if body != nil {
-		bodyBytes, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal body: %w", err)
-		}
-		bodyReader = bytes.NewReader(bodyBytes)
-		bodyStr = string(bodyBytes)
+	// Include query parameters in the path for signature generation
+	pathWithQuery := u.Path
+	if u.RawQuery != "" {
+		pathWithQuery = u.Path + "?" + u.RawQuery
 	}
 
 	// Retry loop
-	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			// Wait before retry
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(retryDelay * time.Duration(attempt)):
-			}
-		}
-
+	start := time.Now()
+	ambiguous := false
+	resigned := false
+	for number := 1; ; number++ {
 		// Rate limiting
 		if err := c.rateLimiter.Wait(ctx); err != nil {
 			return nil, fmt.Errorf("rate limiter error: %w", err)
 		}
 
-		// Create request
-		req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+		resp, err := c.send(ctx, req, u.String(), pathWithQuery)
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
 
-		// Set headers
-		req.Header.Set("Content-Type", "application/json")
-		
-		// Add authentication headers
-		// Include query parameters in the path for signature generation
-		pathWithQuery := u.Path
-		if u.RawQuery != "" {
-			pathWithQuery = u.Path + "?" + u.RawQuery
+		attempt := Attempt{
+			Method:  req.method,
+			Path:    req.path,
+			Number:  number,
+			Err:     err,
+			Elapsed: time.Since(start),
 		}
-		authHeaders, err := c.auth.GetAuthHeaders(method, pathWithQuery, bodyStr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get auth headers: %w", err)
+		if resp != nil {
+			attempt.StatusCode = resp.statusCode
+			attempt.Header = resp.header
 		}
-		for k, v := range authHeaders {
-			req.Header.Set(k, v)
+
+		// Transport failures and server errors leave it unknown whether the
+		// request took effect; rate limited requests were never processed
+		uncertain := err != nil || resp.statusCode >= 500
+		if uncertain {
+			ambiguous = true
 		}
 
-		// Perform request
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("request failed: %w", err)
-			continue
+		failed := err != nil || resp.statusCode >= 400
+		switch {
+		case resp != nil && resp.timestampRejected && c.compensateSkew && !resigned:
+			// The clock offset has just been corrected from this response,
+			// so re-signing once immediately should succeed
+			resigned = true
+			attempt.Retry = true
+		case failed && c.retryPolicy != nil && (!uncertain || req.canResend()):
+			attempt.Delay, attempt.Retry = c.retryPolicy.NextDelay(attempt)
+		}
+		if c.attemptObserver != nil {
+			c.attemptObserver(attempt)
 		}
 
-		// Check for rate limit errors
-		if resp.StatusCode == http.StatusTooManyRequests {
-			resp.Body.Close()
-			lastErr = fmt.Errorf("rate limited (429)")
-			continue
+		if !attempt.Retry {
+			// The final attempt of a keyed request may still have taken effect
+			if failed && ambiguous && req.reconcile != nil {
+				if done, err := req.reconcile(ctx); err == nil && done {
+					return &apiResponse{reconciled: true}, nil
+				}
+			}
+			if resp != nil {
+				resp.attempts = number
+				return resp, nil
+			}
+			return nil, &errors.RequestError{
+				RequestInfo: errors.RequestInfo{Method: req.method, Path: req.path, Attempts: number},
+				Err:         err,
+			}
 		}
 
-		// Check for server errors that should be retried
-		if resp.StatusCode >= 500 {
-			resp.Body.Close()
-			lastErr = fmt.Errorf("server error (%d)", resp.StatusCode)
-			continue
+		// Wait before retry
+		timer := time.NewTimer(attempt.Delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
 		}
 
-		// Success or client error (don't retry)
-		return resp, nil
+		// A keyed request whose previous attempt may have reached the
+		// server is only resent once we know it did not take effect
+		if ambiguous && req.reconcile != nil {
+			done, err := req.reconcile(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to reconcile request: %w", err)
+			}
+			if done {
+				return &apiResponse{reconciled: true}, nil
+			}
+		}
 	}
-
-	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
 }
 
-// do performs a request and handles the response
-func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, result interface{}) error {
-	resp, err := c.request(ctx, method, path, query, body)
+// send performs a single signed attempt of a request
+func (c *Client) send(ctx context.Context, req *apiRequest, rawURL, pathWithQuery string) (*apiResponse, error) {
+	// Create request with a fresh body reader for this attempt
+	var bodyReader io.Reader
+	if req.body != nil {
+		bodyReader = bytes.NewReader(req.body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, rawURL, bodyReader)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	for k, v := range req.header {
+		httpReq.Header[k] = v
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	// Sign each attempt so the timestamp stays fresh
+	signedOffset := c.auth.ClockOffset()
+	authHeaders, err := c.auth.GetAuthHeaders(req.method, pathWithQuery, string(req.body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth headers: %w", err)
+	}
+	for k, v := range authHeaders {
+		httpReq.Header.Set(k, v)
+	}
+
+	// Perform request
+	sent := time.Now()
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	received := time.Now()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return &apiResponse{
+		statusCode:        resp.StatusCode,
+		header:            resp.Header,
+		body:              respBody,
+		timestampRejected: c.observeClock(resp, respBody, signedOffset, sent, received),
+	}, nil
+}
+
+// do performs a request and handles the response
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, result interface{}) error {
+	req := &Request{
+		Method: method,
+		Path:   path,
+		Query:  query,
+		Body:   body,
+		Result: result,
+	}
+	return c.doRequest(ctx, req, nil)
+}
+
+// doRequest runs a request through the middleware chain. The optional
+// reconcile hook is attached to keyed requests, see apiRequest.
+func (c *Client) doRequest(ctx context.Context, req *Request, reconcile func(ctx context.Context) (bool, error)) error {
+	rt := c.chain(func(ctx context.Context, req *Request) (*Response, error) {
+		apiReq, err := newAPIRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		apiReq.reconcile = reconcile
+		return c.roundTrip(ctx, apiReq, req.Result)
+	})
+
+	_, err := rt(ctx, req)
+	return err
+}
+
+// roundTrip performs a prepared request and decodes the response into result
+func (c *Client) roundTrip(ctx context.Context, req *apiRequest, result interface{}) (*Response, error) {
+	resp, err := c.request(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The reconcile hook has already populated the result
+	if resp.reconciled {
+		return &Response{StatusCode: http.StatusOK, Result: result}, nil
+	}
+
+	out := &Response{
+		StatusCode: resp.statusCode,
+		Header:     resp.header,
+		Body:       resp.body,
 	}
 
 	// Check for errors
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return errors.ParseAPIError(respBody, resp.StatusCode)
+	if resp.statusCode < 200 || resp.statusCode >= 300 {
+		err := errors.ParseResponseError(resp.body, resp.statusCode, errors.RequestInfo{
+			Method:   req.method,
+			Path:     req.path,
+			Attempts: resp.attempts,
+			Header:   resp.header,
+		})
+		if resp.timestampRejected {
+			err = &errors.ClockSkewError{Skew: c.ClockSkew(), Err: err}
+		}
+		return out, err
 	}
 
 	// Parse successful response
-	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			return fmt.Errorf("failed to parse response: %w", err)
+	if result != nil && len(resp.body) > 0 {
+		if err := json.Unmarshal(resp.body, result); err != nil {
+			return out, fmt.Errorf("failed to parse response: %w", err)
 		}
+		out.Result = result
 	}
 
-	return nil
+	return out, nil
 }
 
 // AccountService handles account-related endpoints
@@ -225,10 +434,32 @@ func (s *AccountService) GetAccountDetails(ctx context.Context) (*models.Account
 // MarketDataService handles market data endpoints
 type MarketDataService struct {
 	client *Client
+
+	// Cache holds recently fetched quotes when the client was created with
+	// WithQuoteCache, and is nil otherwise
+	Cache *QuoteCache
 }
 
-// GetBestBidAsk fetches the best bid and ask prices for the given symbols
+// GetBestBidAsk fetches the best bid and ask prices for the given symbols,
+// or for every symbol if none are given. With WithQuoteCache, quotes for
+// the given symbols may come from the cache.
 func (s *MarketDataService) GetBestBidAsk(ctx context.Context, symbols ...string) (*models.BestBidAskResponse, error) {
+	if s.Cache != nil && len(symbols) > 0 {
+		return s.Cache.get(ctx, symbols)
+	}
+
+	resp, err := s.fetchBestBidAsk(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+	if s.Cache != nil {
+		s.Cache.store(resp.Results)
+	}
+	return resp, nil
+}
+
+// fetchBestBidAsk requests quotes for symbols from the API
+func (s *MarketDataService) fetchBestBidAsk(ctx context.Context, symbols []string) (*models.BestBidAskResponse, error) {
 	query := url.Values{}
 	for _, symbol := range symbols {
 		query.Add("symbol", strings.ToUpper(symbol))
@@ -242,15 +473,16 @@ func (s *MarketDataService) GetBestBidAsk(ctx context.Context, symbols ...string
 	return &result, nil
 }
 
-// GetEstimatedPrice fetches estimated prices for different quantities
-func (s *MarketDataService) GetEstimatedPrice(ctx context.Context, symbol, side string, quantities ...float64) (*models.EstimatedPriceResponse, error) {
+// GetEstimatedPrice fetches estimated prices for different quantities. The
+// quantities are sent exactly as written.
+func (s *MarketDataService) GetEstimatedPrice(ctx context.Context, symbol, side string, quantities ...models.Decimal) (*models.EstimatedPriceResponse, error) {
 	if side != "bid" && side != "ask" && side != "both" {
 		return nil, fmt.Errorf("invalid side: must be 'bid', 'ask', or 'both'")
 	}
 
 	quantityStrs := make([]string, len(quantities))
 	for i, q := range quantities {
-		quantityStrs[i] = fmt.Sprintf("%g", q)
+		quantityStrs[i] = q.String()
 	}
 
 	query := url.Values{
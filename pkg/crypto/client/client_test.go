@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/auth"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// newTestClient creates a client pointed at a test server running handler
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	privateKey, _, err := auth.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() error = %v", err)
+	}
+
+	// Keep retries fast so tests do not sleep
+	fastRetries := &ExponentialBackoff{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	opts = append([]Option{WithBaseURL(server.URL), WithRetryPolicy(fastRetries)}, opts...)
+	c, err := New("test-api-key", privateKey, opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return c
+}
+
+func TestClassifyRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   idempotency
+	}{
+		{"get", "GET", "/api/v1/crypto/trading/accounts/", "", idempotent},
+		{"cancel", "POST", "/api/v1/crypto/trading/orders/abc/cancel/", "", idempotent},
+		{"keyed order", "POST", ordersPath, `{"client_order_id":"abc"}`, keyedByClientOrderID},
+		{"unkeyed order", "POST", ordersPath, `{"symbol":"BTC-USD"}`, nonIdempotent},
+		{"other post", "POST", "/api/v1/other/", `{}`, nonIdempotent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyRequest(tt.method, tt.path, []byte(tt.body)); got != tt.want {
+				t.Errorf("classifyRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaceOrder_RetryResendsFullBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			// Reconcile lookup finds nothing
+			json.NewEncoder(w).Encode(models.OrdersResponse{})
+		case "POST":
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			bodies = append(bodies, string(body))
+			attempt := len(bodies)
+			mu.Unlock()
+
+			if attempt == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(models.Order{ID: "order-1"})
+		}
+	}, WithOrderQuantization(false))
+
+	order, err := c.Trading.PlaceOrder(context.Background(), &models.PlaceOrderRequest{
+		Symbol:            "BTC-USD",
+		Side:              "buy",
+		Type:              "market",
+		MarketOrderConfig: &models.MarketOrderConfig{AssetQuantity: models.MustParseDecimal("0.1")},
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if order.ID != "order-1" {
+		t.Errorf("order.ID = %q, want %q", order.ID, "order-1")
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("POST attempts = %d, want 2", len(bodies))
+	}
+	if bodies[0] == "" || bodies[0] != bodies[1] {
+		t.Errorf("retry body = %q, want %q", bodies[1], bodies[0])
+	}
+}
+
+func TestPlaceOrder_ReconcilesExistingOrder(t *testing.T) {
+	var mu sync.Mutex
+	posts := 0
+	clientOrderID := "11299b2b-61e3-43e7-b9f7-dee77210bb29"
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(models.OrdersResponse{
+				Results: []models.Order{{ID: "order-1", ClientOrderID: clientOrderID}},
+			})
+		case "POST":
+			mu.Lock()
+			posts++
+			mu.Unlock()
+			// The order was created but the response was lost
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}, WithOrderQuantization(false))
+
+	order, err := c.Trading.PlaceOrder(context.Background(), &models.PlaceOrderRequest{
+		Symbol:            "BTC-USD",
+		ClientOrderID:     clientOrderID,
+		Side:              "buy",
+		Type:              "market",
+		MarketOrderConfig: &models.MarketOrderConfig{AssetQuantity: models.MustParseDecimal("0.1")},
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if order.ID != "order-1" {
+		t.Errorf("order.ID = %q, want %q", order.ID, "order-1")
+	}
+	if posts != 1 {
+		t.Errorf("POST attempts = %d, want 1", posts)
+	}
+}
+
+func TestPlaceOrder_ReconcileUsesServerClock(t *testing.T) {
+	var mu sync.Mutex
+	var starts []string
+	posts := 0
+
+	// The local clock runs ten minutes ahead of the server's
+	serverOffset := -10 * time.Minute
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(serverOffset).UTC().Format(http.TimeFormat))
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case "GET":
+			starts = append(starts, r.URL.Query().Get("created_at_start"))
+			json.NewEncoder(w).Encode(models.OrdersResponse{})
+		case "POST":
+			posts++
+			if posts == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(models.Order{ID: "order-1"})
+		}
+	}, WithOrderQuantization(false))
+
+	// Measure the skew from an earlier response
+	if _, err := c.Trading.GetOrders(context.Background(), nil); err != nil {
+		t.Fatalf("GetOrders() error = %v", err)
+	}
+	if _, err := c.Trading.PlaceOrder(context.Background(), &models.PlaceOrderRequest{
+		Symbol:            "BTC-USD",
+		Side:              "buy",
+		Type:              "market",
+		MarketOrderConfig: &models.MarketOrderConfig{AssetQuantity: models.MustParseDecimal("0.1")},
+	}); err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(starts) != 2 {
+		t.Fatalf("created_at_start = %v, want the reconcile lookup after the warm-up", starts)
+	}
+	since, err := models.ParseTime(starts[1])
+	if err != nil || since.After(time.Now().Add(serverOffset)) {
+		t.Errorf("created_at_start = %s, want it before the server's time", starts[1])
+	}
+}
+
+func TestPlaceOrder_DoesNotModifyRequest(t *testing.T) {
+	var body map[string]any
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(models.Order{ID: "order-1"})
+	}, WithOrderQuantization(false))
+
+	req := &models.PlaceOrderRequest{
+		Symbol:           "btc-usd",
+		Side:             "buy",
+		Type:             "limit",
+		LimitOrderConfig: &models.LimitOrderConfig{AssetQuantity: models.MustParseDecimal("0.1"), LimitPrice: models.NewDecimalFromInt(50000)},
+	}
+	if _, err := c.Trading.PlaceOrder(context.Background(), req); err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+
+	if req.ClientOrderID != "" || req.Symbol != "btc-usd" || req.LimitOrderConfig.TimeInForce != "" {
+		t.Errorf("request = %+v, %+v, want it unchanged", req, req.LimitOrderConfig)
+	}
+	config, _ := body["limit_order_config"].(map[string]any)
+	if body["client_order_id"] == "" || body["symbol"] != "BTC-USD" || config["time_in_force"] != "gtc" {
+		t.Errorf("body = %v, want a generated ID, BTC-USD and gtc", body)
+	}
+}
+
+func TestGetOrders_FormatsFilterTimes(t *testing.T) {
+	var query map[string][]string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		json.NewEncoder(w).Encode(models.OrdersResponse{})
+	})
+
+	eastern := time.FixedZone("EDT", -4*60*60)
+	start := time.Date(2024, 4, 5, 17, 28, 14, 839553000, eastern)
+	if _, err := c.Trading.GetOrders(context.Background(), &models.OrdersFilter{UpdatedAtStart: &start}); err != nil {
+		t.Fatalf("GetOrders() error = %v", err)
+	}
+	if got := query["updated_at_start"]; len(got) != 1 || got[0] != "2024-04-05T21:28:14.839553Z" {
+		t.Errorf("updated_at_start = %v, want the time in UTC to the microsecond", got)
+	}
+}
+
+func TestRequest_NonIdempotentNotRetried(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"type":"server_error","errors":[]}`))
+	})
+
+	err := c.do(context.Background(), "POST", "/api/v1/other/", nil, map[string]string{"a": "b"}, nil)
+	if err == nil {
+		t.Fatal("do() expected error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRequest_TypedErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/crypto/trading/orders/missing/" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type":"client_error","errors":[{"attr":null,"detail":"Not found."}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, err := c.Trading.GetOrder(context.Background(), "missing")
+	if !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("GetOrder() error = %v, want ErrNotFound", err)
+	}
+
+	_, err = c.Account.GetAccountDetails(context.Background())
+	if !stderrors.Is(err, errors.ErrRateLimited) {
+		t.Fatalf("GetAccountDetails() error = %v, want ErrRateLimited", err)
+	}
+	var statusErr *errors.StatusError
+	if !stderrors.As(err, &statusErr) {
+		t.Fatalf("GetAccountDetails() error = %T, want *errors.StatusError", err)
+	}
+	if statusErr.Method != "GET" || statusErr.Path != "/api/v1/crypto/trading/accounts/" {
+		t.Errorf("request = %s %s", statusErr.Method, statusErr.Path)
+	}
+	if statusErr.Attempts != defaultMaxAttempts {
+		t.Errorf("Attempts = %d, want %d", statusErr.Attempts, defaultMaxAttempts)
+	}
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// timestampTolerance is how old the API allows an x-timestamp to be
+	timestampTolerance = 30 * time.Second
+
+	// dateResolution is the granularity of the HTTP Date header
+	dateResolution = time.Second
+
+	// skewSmoothing is the weight given to each new skew sample
+	skewSmoothing = 0.2
+)
+
+// clockSkew estimates the offset between the server and local clocks from
+// Date response headers
+type clockSkew struct {
+	mu       sync.Mutex
+	offset   time.Duration
+	measured bool
+}
+
+// observe folds a new sample into the estimate and returns the estimate
+func (s *clockSkew) observe(sample time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.measured {
+		s.offset = sample
+		s.measured = true
+	} else {
+		s.offset += time.Duration(skewSmoothing * float64(sample-s.offset))
+	}
+	return s.offset
+}
+
+// set replaces the estimate, used when the old one is known to be wrong
+func (s *clockSkew) set(sample time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset = sample
+	s.measured = true
+}
+
+// get returns the current estimate
+func (s *clockSkew) get() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset
+}
+
+// skewSample estimates server time minus local time from a response's Date
+// header. The Date header truncates to the second, so the server time is
+// taken to be the middle of that second, and the local time the midpoint
+// of the round trip.
+func skewSample(header http.Header, sent, received time.Time) (time.Duration, bool) {
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return 0, false
+	}
+	serverTime := date.Add(dateResolution / 2)
+	localTime := sent.Add(received.Sub(sent) / 2)
+	return serverTime.Sub(localTime), true
+}
+
+// ClockSkew returns the measured offset of the server clock from the local
+// clock. It is zero until a response with a Date header has been received.
+func (c *Client) ClockSkew() time.Duration {
+	return c.skew.get()
+}
+
+// observeClock updates the skew estimate from a response and reports whether
+// the timestamp the request was signed with was likely outside the
+// server's tolerance
+func (c *Client) observeClock(resp *http.Response, body []byte, signedOffset time.Duration, sent, received time.Time) bool {
+	sample, ok := skewSample(resp.Header, sent, received)
+	if !ok {
+		return resp.StatusCode == http.StatusUnauthorized && mentionsTimestamp(body)
+	}
+
+	drift := sample - signedOffset
+	if drift < 0 {
+		drift = -drift
+	}
+	rejected := resp.StatusCode == http.StatusUnauthorized &&
+		(mentionsTimestamp(body) || drift >= timestampTolerance-dateResolution)
+
+	// A rejected timestamp means the estimate is stale, so replace it
+	// outright rather than smoothing towards the new sample
+	var offset time.Duration
+	if rejected {
+		c.skew.set(sample)
+		offset = sample
+	} else {
+		offset = c.skew.observe(sample)
+	}
+	if c.compensateSkew {
+		c.auth.SetClockOffset(offset)
+	}
+
+	return rejected
+}
+
+// mentionsTimestamp reports whether an error body refers to the timestamp
+func mentionsTimestamp(body []byte) bool {
+	return bytes.Contains(bytes.ToLower(body), []byte("timestamp"))
+}
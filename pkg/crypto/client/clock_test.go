@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+)
+
+// skewedServer emulates an API whose clock runs ahead of the local clock and
+// which rejects timestamps older than the tolerance
+func skewedServer(skew time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		serverNow := time.Now().Add(skew)
+		w.Header().Set("Date", serverNow.UTC().Format(http.TimeFormat))
+
+		timestamp, _ := strconv.ParseInt(r.Header.Get("x-timestamp"), 10, 64)
+		if age := serverNow.Sub(time.Unix(timestamp, 0)); age > timestampTolerance || age < -timestampTolerance {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"type":"client_error","errors":[{"attr":null,"detail":"Invalid timestamp."}]}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}
+}
+
+func TestClockSkew_Compensated(t *testing.T) {
+	skew := 2 * time.Minute
+	c := newTestClient(t, skewedServer(skew))
+
+	if _, err := c.Account.GetAccountDetails(context.Background()); err != nil {
+		t.Fatalf("GetAccountDetails() error = %v", err)
+	}
+
+	if got := c.ClockSkew(); got < skew-2*time.Second || got > skew+2*time.Second {
+		t.Errorf("ClockSkew() = %v, want about %v", got, skew)
+	}
+
+	// Later requests are signed with the corrected clock from the start
+	attempts := 0
+	c.attemptObserver = func(Attempt) { attempts++ }
+	if _, err := c.Account.GetAccountDetails(context.Background()); err != nil {
+		t.Fatalf("GetAccountDetails() error = %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestClockSkew_ErrorWhenNotCompensated(t *testing.T) {
+	c := newTestClient(t, skewedServer(-time.Minute), WithClockSkewCompensation(false))
+
+	_, err := c.Account.GetAccountDetails(context.Background())
+	var skewErr *errors.ClockSkewError
+	if !stderrors.As(err, &skewErr) {
+		t.Fatalf("GetAccountDetails() error = %v, want *errors.ClockSkewError", err)
+	}
+	if skewErr.Skew > -58*time.Second || skewErr.Skew < -62*time.Second {
+		t.Errorf("Skew = %v, want about -1m", skewErr.Skew)
+	}
+}
+
+func TestSkewSample(t *testing.T) {
+	sent := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	received := sent.Add(200 * time.Millisecond)
+
+	header := http.Header{}
+	header.Set("Date", sent.Add(10*time.Second).Format(http.TimeFormat))
+
+	got, ok := skewSample(header, sent, received)
+	if !ok {
+		t.Fatal("skewSample() ok = false, want true")
+	}
+	// Server time is taken as the middle of the Date second, local time as
+	// the midpoint of the round trip
+	if want := 10*time.Second + 400*time.Millisecond; got != want {
+		t.Errorf("skewSample() = %v, want %v", got, want)
+	}
+
+	if _, ok := skewSample(http.Header{}, sent, received); ok {
+		t.Error("skewSample() ok = true without Date header")
+	}
+}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+const (
+	// ratePrecision is the number of decimal places kept when inverting a
+	// price into a rate
+	ratePrecision = 18
+
+	// maxConversionLegs bounds the paths searched between two assets
+	maxConversionLegs = 3
+)
+
+// half is used to take the midpoint of a bid and ask exactly
+var half = models.NewDecimal(5, -1)
+
+// ConversionGraph converts between assets through trading pairs, using a
+// snapshot of their best bid and ask prices. With every pair quoted in USD,
+// converting ETH to BTC sells ETH for USD and buys BTC with it.
+type ConversionGraph struct {
+	edges map[string][]conversionEdge
+}
+
+// conversionEdge is one direction of a trading pair
+type conversionEdge struct {
+	to  string
+	leg ConversionLeg
+
+	// rate and mid are units of to received per unit given
+	rate models.Decimal
+	mid  models.Decimal
+}
+
+// ConversionLeg is one trade in a conversion
+type ConversionLeg struct {
+	Symbol string
+
+	// Side is "bid" when selling the pair's asset and "ask" when buying it
+	Side string
+
+	// Price is the bid or ask, inclusive of spread, the leg trades at
+	Price models.Decimal
+}
+
+// Conversion is the best way found to convert one asset into another
+type Conversion struct {
+	From string
+	To   string
+
+	// Path lists the assets converted through, starting with From and
+	// ending with To
+	Path []string
+	Legs []ConversionLeg
+
+	// Rate is the units of To received per unit of From after paying the
+	// spread on every leg
+	Rate models.Decimal
+
+	// MidRate is the implied rate at mid prices, before spread
+	MidRate models.Decimal
+
+	// CostBps is how much worse Rate is than MidRate, in basis points
+	CostBps float64
+}
+
+// Amount returns the units of To received for amount of From
+func (c *Conversion) Amount(amount models.Decimal) models.Decimal {
+	return amount.Mul(c.Rate)
+}
+
+// NewConversionGraph builds a graph from trading pairs and best bid/ask
+// quotes for them. Pairs that are not tradable or have no two-sided quote
+// are left out.
+func NewConversionGraph(pairs []models.TradingPair, quotes []models.BestBidAskResult) *ConversionGraph {
+	bySymbol := make(map[string]models.BestBidAskResult, len(quotes))
+	for _, quote := range quotes {
+		bySymbol[strings.ToUpper(quote.Symbol)] = quote
+	}
+
+	g := &ConversionGraph{edges: make(map[string][]conversionEdge)}
+	for _, pair := range pairs {
+		symbol := strings.ToUpper(pair.Symbol)
+		quote, ok := bySymbol[symbol]
+		if !ok || pair.Status != tradableStatus {
+			continue
+		}
+
+		bid := quote.BidInclusiveOfSellSpread
+		ask := quote.AskInclusiveOfBuySpread
+		if bid.Sign() <= 0 || ask.Sign() <= 0 {
+			continue
+		}
+		mid := bid.Add(ask).Mul(half)
+		one := models.NewDecimalFromInt(1)
+
+		asset := strings.ToUpper(pair.AssetCode)
+		quoteCode := strings.ToUpper(pair.QuoteCode)
+
+		// Selling the asset receives the bid; buying it spends the ask
+		g.edges[asset] = append(g.edges[asset], conversionEdge{
+			to:   quoteCode,
+			leg:  ConversionLeg{Symbol: symbol, Side: "bid", Price: bid},
+			rate: bid,
+			mid:  mid,
+		})
+		g.edges[quoteCode] = append(g.edges[quoteCode], conversionEdge{
+			to:   asset,
+			leg:  ConversionLeg{Symbol: symbol, Side: "ask", Price: ask},
+			rate: one.Div(ask, ratePrecision),
+			mid:  one.Div(mid, ratePrecision),
+		})
+	}
+	return g
+}
+
+// GetConversionGraph fetches every tradable pair and its best bid and ask
+// and builds a conversion graph from them
+func (c *Client) GetConversionGraph(ctx context.Context) (*ConversionGraph, error) {
+	var pairs []models.TradingPair
+	var symbols []string
+	for pair, err := range c.Trading.AllTradingPairs(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trading pairs: %w", err)
+		}
+		pairs = append(pairs, pair)
+		if pair.Status == tradableStatus {
+			symbols = append(symbols, pair.Symbol)
+		}
+	}
+	c.Instruments.store(pairs)
+
+	var quotes []models.BestBidAskResult
+	for batch := range slices.Chunk(symbols, defaultQuoteSymbolsPerReq) {
+		resp, err := c.MarketData.GetBestBidAsk(ctx, batch...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get best bid/ask: %w", err)
+		}
+		quotes = append(quotes, resp.Results...)
+	}
+
+	return NewConversionGraph(pairs, quotes), nil
+}
+
+// Assets returns every asset in the graph, sorted
+func (g *ConversionGraph) Assets() []string {
+	assets := make([]string, 0, len(g.edges))
+	for asset := range g.edges {
+		assets = append(assets, asset)
+	}
+	slices.Sort(assets)
+	return assets
+}
+
+// Convert finds the conversion from one asset to another that receives the
+// most after spread, through at most three legs
+func (g *ConversionGraph) Convert(from, to string) (*Conversion, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+	if from == to {
+		return nil, fmt.Errorf("%w: cannot convert %s to itself", errors.ErrValidation, from)
+	}
+
+	var best *Conversion
+	var path []conversionEdge
+	visited := map[string]bool{from: true}
+
+	var search func(asset string)
+	search = func(asset string) {
+		if asset == to {
+			if conv := newConversion(from, path); best == nil || conv.Rate.GreaterThan(best.Rate) {
+				best = conv
+			}
+			return
+		}
+		if len(path) == maxConversionLegs {
+			return
+		}
+		for _, edge := range g.edges[asset] {
+			if visited[edge.to] {
+				continue
+			}
+			visited[edge.to] = true
+			path = append(path, edge)
+			search(edge.to)
+			path = path[:len(path)-1]
+			visited[edge.to] = false
+		}
+	}
+	search(from)
+
+	if best == nil {
+		return nil, fmt.Errorf("%w: no conversion from %s to %s", errors.ErrNotFound, from, to)
+	}
+	return best, nil
+}
+
+// ImpliedRate returns the price of base in units of quote at mid prices,
+// such as ImpliedRate("ETH", "BTC") for ETH/BTC
+func (g *ConversionGraph) ImpliedRate(base, quote string) (models.Decimal, error) {
+	conv, err := g.Convert(base, quote)
+	if err != nil {
+		return models.Decimal{}, err
+	}
+	return conv.MidRate, nil
+}
+
+// newConversion multiplies out the rates along a path
+func newConversion(from string, path []conversionEdge) *Conversion {
+	conv := &Conversion{
+		From:    from,
+		To:      path[len(path)-1].to,
+		Path:    []string{from},
+		Rate:    models.NewDecimalFromInt(1),
+		MidRate: models.NewDecimalFromInt(1),
+	}
+	for _, edge := range path {
+		conv.Path = append(conv.Path, edge.to)
+		conv.Legs = append(conv.Legs, edge.leg)
+		conv.Rate = conv.Rate.Mul(edge.rate).Round(ratePrecision)
+		conv.MidRate = conv.MidRate.Mul(edge.mid).Round(ratePrecision)
+	}
+
+	if conv.MidRate.Sign() > 0 {
+		conv.CostBps = conv.MidRate.Sub(conv.Rate).Mul(bpsPerUnit).Div(conv.MidRate, 4).Float64()
+	}
+	return conv
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"testing"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+func usdPair(asset, status string) models.TradingPair {
+	return models.TradingPair{Symbol: asset + "-USD", AssetCode: asset, QuoteCode: "USD", Status: status}
+}
+
+func bidAsk(symbol, bid, ask string) models.BestBidAskResult {
+	return models.BestBidAskResult{
+		Symbol:                   symbol,
+		BidInclusiveOfSellSpread: models.MustParseDecimal(bid),
+		AskInclusiveOfBuySpread:  models.MustParseDecimal(ask),
+	}
+}
+
+func testConversionGraph() *ConversionGraph {
+	pairs := []models.TradingPair{usdPair("BTC", "tradable"), usdPair("ETH", "tradable"), usdPair("DOGE", "untradable")}
+	quotes := []models.BestBidAskResult{
+		bidAsk("BTC-USD", "49900", "50100"),
+		bidAsk("ETH-USD", "2990", "3010"),
+		bidAsk("DOGE-USD", "0.1", "0.11"),
+	}
+	return NewConversionGraph(pairs, quotes)
+}
+
+func TestConversionGraph_ImpliedRate(t *testing.T) {
+	g := testConversionGraph()
+
+	rate, err := g.ImpliedRate("eth", "btc")
+	if err != nil {
+		t.Fatalf("ImpliedRate() error = %v", err)
+	}
+	if want := models.MustParseDecimal("0.06"); !rate.Equal(want) {
+		t.Errorf("ETH/BTC = %s, want %s", rate, want)
+	}
+
+	rate, err = g.ImpliedRate("BTC", "ETH")
+	if err != nil {
+		t.Fatalf("ImpliedRate() error = %v", err)
+	}
+	if got := rate.Round(6).String(); got != "16.666667" {
+		t.Errorf("BTC/ETH = %s, want 16.666667", got)
+	}
+}
+
+func TestConversionGraph_Convert(t *testing.T) {
+	g := testConversionGraph()
+
+	conv, err := g.Convert("ETH", "BTC")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	if len(conv.Path) != 3 || conv.Path[1] != "USD" {
+		t.Errorf("Path = %v, want ETH USD BTC", conv.Path)
+	}
+	if len(conv.Legs) != 2 || conv.Legs[0].Side != "bid" || conv.Legs[1].Side != "ask" || conv.Legs[1].Symbol != "BTC-USD" {
+		t.Errorf("Legs = %+v, want sell ETH-USD then buy BTC-USD", conv.Legs)
+	}
+
+	// Selling at the 2990 bid and buying at the 50100 ask
+	if got := conv.Rate.Round(10).String(); got != "0.0596806387" {
+		t.Errorf("Rate = %s, want 0.0596806387", got)
+	}
+	if conv.CostBps != 53.2269 {
+		t.Errorf("CostBps = %v, want 53.2269", conv.CostBps)
+	}
+	if got := conv.Amount(models.NewDecimalFromInt(10)).Round(8).String(); got != "0.59680639" {
+		t.Errorf("Amount(10) = %s, want 0.59680639", got)
+	}
+
+	// A single leg
+	conv, err = g.Convert("USD", "ETH")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if len(conv.Legs) != 1 || !conv.Legs[0].Price.Equal(models.NewDecimalFromInt(3010)) {
+		t.Errorf("Legs = %+v, want one buy at 3010", conv.Legs)
+	}
+}
+
+func TestConversionGraph_NoPath(t *testing.T) {
+	g := testConversionGraph()
+
+	if _, err := g.Convert("DOGE", "BTC"); !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("Convert() from an untradable asset error = %v, want ErrNotFound", err)
+	}
+	if _, err := g.Convert("BTC", "btc"); !stderrors.Is(err, errors.ErrValidation) {
+		t.Errorf("Convert() to the same asset error = %v, want ErrValidation", err)
+	}
+	if assets := g.Assets(); len(assets) != 3 || assets[0] != "BTC" || assets[2] != "USD" {
+		t.Errorf("Assets() = %v, want BTC ETH USD", assets)
+	}
+}
+
+func TestGetConversionGraph(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case tradingPairsPath:
+			json.NewEncoder(w).Encode(models.TradingPairsResponse{Results: []models.TradingPair{usdPair("BTC", "tradable"), usdPair("ETH", "tradable")}})
+		default:
+			json.NewEncoder(w).Encode(models.BestBidAskResponse{Results: []models.BestBidAskResult{
+				bidAsk("BTC-USD", "49900", "50100"),
+				bidAsk("ETH-USD", "2990", "3010"),
+			}})
+		}
+	})
+
+	g, err := c.GetConversionGraph(context.Background())
+	if err != nil {
+		t.Fatalf("GetConversionGraph() error = %v", err)
+	}
+	if _, err := g.Convert("BTC", "ETH"); err != nil {
+		t.Errorf("Convert() error = %v", err)
+	}
+	if _, ok := c.Instruments.cached("ETH-USD"); !ok {
+		t.Error("trading pairs were not stored in the instrument registry")
+	}
+}
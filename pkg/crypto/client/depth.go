@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// defaultDepthQuantitiesPerReq is the default number of quantities sent in
+// one estimated price request
+const defaultDepthQuantitiesPerReq = 10
+
+// bpsPerUnit converts a fraction to basis points
+var bpsPerUnit = models.NewDecimalFromInt(10000)
+
+// DepthProbeConfig configures a DepthProbe
+type DepthProbeConfig struct {
+	// Quantities is the ladder of asset quantities to price on each side.
+	// It must not be empty; GeometricLadder builds a typical one.
+	Quantities []models.Decimal
+
+	// MaxQuantitiesPerRequest is the largest number of quantities priced in
+	// one request. Larger ladders are split. Defaults to 10.
+	MaxQuantitiesPerRequest int
+}
+
+// GeometricLadder returns n quantities starting at first, each ratio times
+// the previous one
+func GeometricLadder(first, ratio models.Decimal, n int) []models.Decimal {
+	ladder := make([]models.Decimal, 0, n)
+	q := first
+	for range n {
+		ladder = append(ladder, q)
+		q = q.Mul(ratio)
+	}
+	return ladder
+}
+
+// DepthProbe samples estimated execution prices across a ladder of
+// quantities to build a synthetic order book for a symbol. The API does not
+// publish its order book, so this is the closest view of available depth.
+type DepthProbe struct {
+	marketData *MarketDataService
+	config     DepthProbeConfig
+}
+
+// DepthPoint is the estimated cost of trading one quantity on the ladder
+type DepthPoint struct {
+	Quantity models.Decimal
+
+	// Price is the estimated average execution price, including the spread
+	Price models.Decimal
+
+	// SlippageBps is how much worse Price is than the best bid or ask, in
+	// basis points
+	SlippageBps float64
+
+	// MarginalPrice is the average price of the units between the previous
+	// point and this one, and MarginalImpactBps how much worse it is than
+	// the best bid or ask
+	MarginalPrice     models.Decimal
+	MarginalImpactBps float64
+}
+
+// DepthCurve is a synthetic slippage curve for both sides of a symbol
+type DepthCurve struct {
+	Symbol string
+
+	// Bid and Ask are the best prices, inclusive of spread, that slippage
+	// is measured from
+	Bid models.Decimal
+	Ask models.Decimal
+
+	// Bids holds the cost of selling each quantity and Asks of buying it,
+	// in increasing order of quantity
+	Bids []DepthPoint
+	Asks []DepthPoint
+}
+
+// NewDepthProbe creates a probe for a quantity ladder
+func (s *MarketDataService) NewDepthProbe(config DepthProbeConfig) (*DepthProbe, error) {
+	if len(config.Quantities) == 0 {
+		return nil, fmt.Errorf("%w: depth probe needs at least one quantity", errors.ErrValidation)
+	}
+	if config.MaxQuantitiesPerRequest <= 0 {
+		config.MaxQuantitiesPerRequest = defaultDepthQuantitiesPerReq
+	}
+
+	ladder := make([]models.Decimal, 0, len(config.Quantities))
+	for _, q := range config.Quantities {
+		if q.Sign() <= 0 {
+			return nil, fmt.Errorf("%w: depth probe quantity %s must be positive", errors.ErrValidation, q)
+		}
+		if !slices.ContainsFunc(ladder, q.Equal) {
+			ladder = append(ladder, q)
+		}
+	}
+	slices.SortFunc(ladder, models.Decimal.Cmp)
+	config.Quantities = ladder
+
+	return &DepthProbe{marketData: s, config: config}, nil
+}
+
+// Quantities returns the probe's ladder in increasing order
+func (p *DepthProbe) Quantities() []models.Decimal {
+	return slices.Clone(p.config.Quantities)
+}
+
+// Probe prices every quantity on the ladder for both sides of symbol. It
+// makes one best bid/ask request and one estimated price request per
+// MaxQuantitiesPerRequest quantities.
+func (p *DepthProbe) Probe(ctx context.Context, symbol string) (*DepthCurve, error) {
+	symbol = strings.ToUpper(symbol)
+
+	best, err := p.marketData.GetBestBidAsk(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best bid/ask: %w", err)
+	}
+	if len(best.Results) == 0 {
+		return nil, fmt.Errorf("no quote for %s", symbol)
+	}
+
+	curve := &DepthCurve{
+		Symbol: symbol,
+		Bid:    best.Results[0].BidInclusiveOfSellSpread,
+		Ask:    best.Results[0].AskInclusiveOfBuySpread,
+	}
+	if curve.Bid.Sign() <= 0 || curve.Ask.Sign() <= 0 {
+		return nil, fmt.Errorf("no bid or ask for %s", symbol)
+	}
+
+	var bids, asks []models.EstimatedPriceResult
+	for batch := range slices.Chunk(p.config.Quantities, p.config.MaxQuantitiesPerRequest) {
+		resp, err := p.marketData.GetEstimatedPrice(ctx, symbol, "both", batch...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get estimated prices: %w", err)
+		}
+		for _, result := range resp.Results {
+			switch strings.ToLower(result.Side) {
+			case "bid":
+				bids = append(bids, result)
+			case "ask":
+				asks = append(asks, result)
+			}
+		}
+	}
+
+	curve.Bids = depthPoints(bids, curve.Bid, -1)
+	curve.Asks = depthPoints(asks, curve.Ask, 1)
+	return curve, nil
+}
+
+// depthPoints builds a curve from estimated prices. direction is 1 when
+// higher prices are worse, for buying, and -1 when lower prices are worse.
+func depthPoints(results []models.EstimatedPriceResult, best models.Decimal, direction int64) []DepthPoint {
+	slices.SortFunc(results, func(a, b models.EstimatedPriceResult) int {
+		return a.Quantity.Cmp(b.Quantity)
+	})
+
+	sign := models.NewDecimalFromInt(direction)
+	impact := func(price models.Decimal) float64 {
+		return price.Sub(best).Mul(sign).Mul(bpsPerUnit).Div(best, 4).Float64()
+	}
+
+	points := make([]DepthPoint, 0, len(results))
+	var prevQty, prevCost models.Decimal
+	for _, result := range results {
+		price := result.AskInclusiveOfBuySpread
+		if direction < 0 {
+			price = result.BidInclusiveOfSellSpread
+		}
+		if price.IsZero() {
+			price = result.Price
+		}
+		if result.Quantity.Sign() <= 0 || price.Sign() <= 0 || !result.Quantity.GreaterThan(prevQty) {
+			continue
+		}
+
+		// The units between the previous point and this one cost the
+		// difference in total cost
+		cost := result.Quantity.Mul(price)
+		marginal := cost.Sub(prevCost).Div(result.Quantity.Sub(prevQty), price.Scale()+2)
+
+		points = append(points, DepthPoint{
+			Quantity:          result.Quantity,
+			Price:             price,
+			SlippageBps:       impact(price),
+			MarginalPrice:     marginal,
+			MarginalImpactBps: impact(marginal),
+		})
+		prevQty, prevCost = result.Quantity, cost
+	}
+	return points
+}
+
+// MaxSizeWithin returns the largest quantity on side, "bid" to sell or "ask"
+// to buy, whose average slippage is within bps basis points. Between ladder
+// points slippage is interpolated linearly, and the result is rounded down
+// to the precision of the ladder. It returns zero if even the smallest
+// quantity exceeds the tolerance, and the largest quantity probed if every
+// quantity is within it.
+func (c *DepthCurve) MaxSizeWithin(side string, bps float64) models.Decimal {
+	points := c.Asks
+	if strings.EqualFold(side, "bid") {
+		points = c.Bids
+	}
+
+	var size models.Decimal
+	for i, point := range points {
+		if point.SlippageBps <= bps {
+			size = point.Quantity
+			continue
+		}
+		if i == 0 {
+			return models.Decimal{}
+		}
+
+		prev := points[i-1]
+		fraction := (bps - prev.SlippageBps) / (point.SlippageBps - prev.SlippageBps)
+		places := max(prev.Quantity.Scale(), point.Quantity.Scale())
+		step := point.Quantity.Sub(prev.Quantity).Mul(models.NewDecimalFromFloat(fraction))
+		return prev.Quantity.Add(step).RoundMode(places, models.RoundDown)
+	}
+	return size
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// depthHandler quotes BTC-USD at 100/101, with prices moving 1 per unit of
+// quantity on each side
+func depthHandler(requests *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "best_bid_ask") {
+			json.NewEncoder(w).Encode(models.BestBidAskResponse{Results: []models.BestBidAskResult{{
+				Symbol:                   "BTC-USD",
+				BidInclusiveOfSellSpread: models.NewDecimalFromInt(100),
+				AskInclusiveOfBuySpread:  models.NewDecimalFromInt(101),
+			}}})
+			return
+		}
+
+		quantities := r.URL.Query().Get("quantity")
+		*requests = append(*requests, quantities)
+
+		var resp models.EstimatedPriceResponse
+		for _, s := range strings.Split(quantities, ",") {
+			q := models.MustParseDecimal(s)
+			resp.Results = append(resp.Results,
+				models.EstimatedPriceResult{Symbol: "BTC-USD", Side: "bid", Quantity: q, BidInclusiveOfSellSpread: models.NewDecimalFromInt(100).Sub(q)},
+				models.EstimatedPriceResult{Symbol: "BTC-USD", Side: "ask", Quantity: q, AskInclusiveOfBuySpread: models.NewDecimalFromInt(101).Add(q)},
+			)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestDepthProbe(t *testing.T) {
+	var requests []string
+	c := newTestClient(t, depthHandler(&requests))
+
+	probe, err := c.MarketData.NewDepthProbe(DepthProbeConfig{
+		Quantities:              GeometricLadder(models.MustParseDecimal("0.5"), models.NewDecimalFromInt(2), 4),
+		MaxQuantitiesPerRequest: 3,
+	})
+	if err != nil {
+		t.Fatalf("NewDepthProbe() error = %v", err)
+	}
+
+	curve, err := probe.Probe(context.Background(), "btc-usd")
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+
+	if len(requests) != 2 || requests[0] != "0.5,1.0,2.0" || requests[1] != "4.0" {
+		t.Errorf("requests = %q, want ladder split into 3 and 1", requests)
+	}
+	if len(curve.Bids) != 4 || len(curve.Asks) != 4 {
+		t.Fatalf("curve has %d bids and %d asks, want 4 each", len(curve.Bids), len(curve.Asks))
+	}
+
+	// Buying 2 costs 103 on average, 2/101 or about 198 bps over the ask; the units
+	// between 1 and 2 cost 2*103 - 1*102 = 104
+	ask := curve.Asks[2]
+	if !ask.Price.Equal(models.NewDecimalFromInt(103)) || ask.SlippageBps != 198.0198 {
+		t.Errorf("ask at 2 = %+v", ask)
+	}
+	if !ask.MarginalPrice.Equal(models.NewDecimalFromInt(104)) {
+		t.Errorf("marginal price = %s, want 104", ask.MarginalPrice)
+	}
+
+	// Selling 1 averages 99, 100 bps under the 100 bid
+	if bid := curve.Bids[1]; bid.SlippageBps != 100 || bid.MarginalImpactBps != 150 {
+		t.Errorf("bid at 1 = %+v", bid)
+	}
+}
+
+func TestDepthCurve_MaxSizeWithin(t *testing.T) {
+	var requests []string
+	c := newTestClient(t, depthHandler(&requests))
+
+	probe, err := c.MarketData.NewDepthProbe(DepthProbeConfig{Quantities: []models.Decimal{
+		models.NewDecimalFromInt(4), models.NewDecimalFromInt(1), models.NewDecimalFromInt(2), models.NewDecimalFromInt(1),
+	}})
+	if err != nil {
+		t.Fatalf("NewDepthProbe() error = %v", err)
+	}
+	if got := len(probe.Quantities()); got != 3 {
+		t.Errorf("len(Quantities()) = %d, want duplicates removed", got)
+	}
+
+	curve, err := probe.Probe(context.Background(), "BTC-USD")
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+
+	tests := []struct {
+		side string
+		bps  float64
+		want string
+	}{
+		{"bid", 50, "0"},
+		{"bid", 100, "1"},
+		{"bid", 150, "1"}, // halfway from 1 to 2, rounded down to whole units
+		{"bid", 300, "3"},
+		{"bid", 1000, "4"},
+		{"ask", 10, "0"},
+	}
+
+	for _, tt := range tests {
+		if got := curve.MaxSizeWithin(tt.side, tt.bps); got.String() != tt.want {
+			t.Errorf("MaxSizeWithin(%s, %v) = %s, want %s", tt.side, tt.bps, got, tt.want)
+		}
+	}
+}
+
+func TestNewDepthProbe_Validation(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	if _, err := c.MarketData.NewDepthProbe(DepthProbeConfig{}); err == nil {
+		t.Error("NewDepthProbe() with no quantities succeeded, want error")
+	}
+	if _, err := c.MarketData.NewDepthProbe(DepthProbeConfig{Quantities: []models.Decimal{models.NewDecimalFromInt(-1)}}); err == nil {
+		t.Error("NewDepthProbe() with a negative quantity succeeded, want error")
+	}
+}
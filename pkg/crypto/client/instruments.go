@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+const (
+	tradingPairsPath = "/api/v1/crypto/trading/trading_pairs/"
+
+	// defaultInstrumentTTL is how long trading pair details are cached
+	defaultInstrumentTTL = time.Hour
+)
+
+// InstrumentRegistry caches trading pair details fetched with
+// GetTradingPairs. PlaceOrder uses it to round orders to the pair's
+// increments and to check order sizes before sending.
+type InstrumentRegistry struct {
+	trading *TradingService
+
+	mu    sync.RWMutex
+	ttl   time.Duration
+	pairs map[string]instrument
+}
+
+// instrument is a cached trading pair
+type instrument struct {
+	pair    models.TradingPair
+	fetched time.Time
+}
+
+func newInstrumentRegistry(trading *TradingService) *InstrumentRegistry {
+	return &InstrumentRegistry{
+		trading: trading,
+		ttl:     defaultInstrumentTTL,
+		pairs:   make(map[string]instrument),
+	}
+}
+
+// SetTTL sets how long trading pair details are cached before being fetched
+// again. A zero TTL disables expiry.
+func (r *InstrumentRegistry) SetTTL(ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ttl = ttl
+}
+
+// Get returns the trading pair for symbol, fetching it if it is not cached
+// or has expired
+func (r *InstrumentRegistry) Get(ctx context.Context, symbol string) (*models.TradingPair, error) {
+	symbol = strings.ToUpper(symbol)
+	if pair, ok := r.cached(symbol); ok {
+		return pair, nil
+	}
+
+	resp, err := r.trading.GetTradingPairs(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trading pair %s: %w", symbol, err)
+	}
+	r.store(resp.Results)
+
+	for i := range resp.Results {
+		if resp.Results[i].Symbol == symbol {
+			return &resp.Results[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown trading pair %q", errors.ErrValidation, symbol)
+}
+
+// Refresh fetches every trading pair, replacing the cached details
+func (r *InstrumentRegistry) Refresh(ctx context.Context) error {
+	var pairs []models.TradingPair
+	for pair, err := range r.trading.AllTradingPairs(ctx) {
+		if err != nil {
+			return fmt.Errorf("failed to get trading pairs: %w", err)
+		}
+		pairs = append(pairs, pair)
+	}
+
+	now := time.Now()
+	cache := make(map[string]instrument, len(pairs))
+	for _, pair := range pairs {
+		cache[strings.ToUpper(pair.Symbol)] = instrument{pair: pair, fetched: now}
+	}
+
+	r.mu.Lock()
+	r.pairs = cache
+	r.mu.Unlock()
+	return nil
+}
+
+// Invalidate removes the given symbols from the cache, or every symbol if
+// none are given
+func (r *InstrumentRegistry) Invalidate(symbols ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(symbols) == 0 {
+		r.pairs = make(map[string]instrument)
+		return
+	}
+	for _, symbol := range symbols {
+		delete(r.pairs, strings.ToUpper(symbol))
+	}
+}
+
+// cached returns the cached pair for symbol if it has not expired
+func (r *InstrumentRegistry) cached(symbol string) (*models.TradingPair, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	inst, ok := r.pairs[symbol]
+	if !ok || (r.ttl > 0 && time.Since(inst.fetched) > r.ttl) {
+		return nil, false
+	}
+	pair := inst.pair
+	return &pair, true
+}
+
+// store caches pairs as fetched now
+func (r *InstrumentRegistry) store(pairs []models.TradingPair) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, pair := range pairs {
+		r.pairs[strings.ToUpper(pair.Symbol)] = instrument{pair: pair, fetched: now}
+	}
+}
+
+// quantizeOrder returns a copy of req whose order config has quantities and
+// prices rounded to the pair's increments. Asset quantities are checked
+// against the pair's minimum and maximum order size after rounding.
+func quantizeOrder(req *models.PlaceOrderRequest, pair *models.TradingPair, mode models.RoundingMode) (*models.PlaceOrderRequest, error) {
+	if pair.Status != tradableStatus {
+		return nil, fmt.Errorf("%w: %s is not tradable (status %q)", errors.ErrValidation, pair.Symbol, pair.Status)
+	}
+
+	q := quantizer{pair: pair, mode: mode}
+	out := *req
+
+	switch req.Type {
+	case models.OrderTypeMarket:
+		config := *req.MarketOrderConfig
+		if err := q.amounts("market_order_config", "MarketOrderConfig", &config.AssetQuantity, &config.QuoteAmount); err != nil {
+			return nil, err
+		}
+		out.MarketOrderConfig = &config
+	case models.OrderTypeLimit:
+		config := *req.LimitOrderConfig
+		if err := q.amounts("limit_order_config", "LimitOrderConfig", &config.AssetQuantity, &config.QuoteAmount); err != nil {
+			return nil, err
+		}
+		config.LimitPrice = q.price(config.LimitPrice)
+		out.LimitOrderConfig = &config
+	case models.OrderTypeStopLoss:
+		config := *req.StopLossOrderConfig
+		if err := q.amounts("stop_loss_order_config", "StopLossOrderConfig", &config.AssetQuantity, &config.QuoteAmount); err != nil {
+			return nil, err
+		}
+		config.StopPrice = q.price(config.StopPrice)
+		out.StopLossOrderConfig = &config
+	case models.OrderTypeStopLimit:
+		config := *req.StopLimitOrderConfig
+		if err := q.amounts("stop_limit_order_config", "StopLimitOrderConfig", &config.AssetQuantity, &config.QuoteAmount); err != nil {
+			return nil, err
+		}
+		config.LimitPrice = q.price(config.LimitPrice)
+		config.StopPrice = q.price(config.StopPrice)
+		out.StopLimitOrderConfig = &config
+	}
+
+	return &out, nil
+}
+
+// quantizer rounds order amounts for a trading pair
+type quantizer struct {
+	pair *models.TradingPair
+	mode models.RoundingMode
+}
+
+// price rounds a price to the quote increment
+func (q quantizer) price(price models.Decimal) models.Decimal {
+	return price.Quantize(q.pair.QuoteIncrement, q.mode)
+}
+
+// amounts rounds whichever of the asset quantity and quote amount is set,
+// checking the asset quantity against the order size limits
+func (q quantizer) amounts(attr, field string, assetQuantity, quoteAmount *models.Decimal) error {
+	if !quoteAmount.IsZero() {
+		*quoteAmount = quoteAmount.Quantize(q.pair.QuoteIncrement, q.mode)
+		return nil
+	}
+
+	*assetQuantity = assetQuantity.Quantize(q.pair.AssetIncrement, q.mode)
+
+	minSize, maxSize := q.pair.MinOrderSize, q.pair.MaxOrderSize
+	if assetQuantity.IsZero() || assetQuantity.LessThan(minSize) || (!maxSize.IsZero() && assetQuantity.GreaterThan(maxSize)) {
+		return &errors.OrderSizeError{
+			Symbol:   q.pair.Symbol,
+			Attr:     attr + ".asset_quantity",
+			Field:    field + ".AssetQuantity",
+			Quantity: *assetQuantity,
+			Min:      minSize,
+			Max:      maxSize,
+		}
+	}
+	return nil
+}
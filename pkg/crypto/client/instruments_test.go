@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+var btcUSD = models.TradingPair{
+	AssetCode:      "BTC",
+	QuoteCode:      "USD",
+	QuoteIncrement: models.MustParseDecimal("0.01"),
+	AssetIncrement: models.MustParseDecimal("0.00001"),
+	MaxOrderSize:   models.MustParseDecimal("20"),
+	MinOrderSize:   models.MustParseDecimal("0.0001"),
+	Status:         "tradable",
+	Symbol:         "BTC-USD",
+}
+
+// instrumentServer serves btcUSD as the only trading pair and records the
+// bodies of placed orders
+type instrumentServer struct {
+	mu          sync.Mutex
+	pairLookups int
+	orders      []string
+}
+
+func (s *instrumentServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case tradingPairsPath:
+		s.pairLookups++
+		json.NewEncoder(w).Encode(models.TradingPairsResponse{Results: []models.TradingPair{btcUSD}})
+	case ordersPath:
+		body, _ := io.ReadAll(r.Body)
+		s.orders = append(s.orders, string(body))
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(models.Order{ID: "order-1"})
+	}
+}
+
+func TestPlaceOrder_Quantizes(t *testing.T) {
+	server := &instrumentServer{}
+	c := newTestClient(t, server.handle)
+
+	req := &models.PlaceOrderRequest{
+		Symbol: "btc-usd",
+		Side:   "buy",
+		Type:   "limit",
+		LimitOrderConfig: &models.LimitOrderConfig{
+			AssetQuantity: models.MustParseDecimal("0.123456789"),
+			LimitPrice:    models.MustParseDecimal("45000.129"),
+		},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.Trading.PlaceOrder(context.Background(), req); err != nil {
+			t.Fatalf("PlaceOrder() error = %v", err)
+		}
+	}
+
+	var body struct {
+		LimitOrderConfig models.LimitOrderConfig `json:"limit_order_config"`
+	}
+	if err := json.Unmarshal([]byte(server.orders[0]), &body); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got := body.LimitOrderConfig.AssetQuantity.String(); got != "0.12345" {
+		t.Errorf("asset_quantity = %s, want 0.12345", got)
+	}
+	if got := body.LimitOrderConfig.LimitPrice.String(); got != "45000.12" {
+		t.Errorf("limit_price = %s, want 45000.12", got)
+	}
+
+	// The caller's amounts are left as they were
+	if got := req.LimitOrderConfig.AssetQuantity.String(); got != "0.123456789" {
+		t.Errorf("request AssetQuantity = %s, want unchanged", got)
+	}
+
+	// The trading pair is fetched once and cached
+	if server.pairLookups != 1 {
+		t.Errorf("trading pair lookups = %d, want 1", server.pairLookups)
+	}
+}
+
+func TestPlaceOrder_RoundingMode(t *testing.T) {
+	server := &instrumentServer{}
+	c := newTestClient(t, server.handle, WithRoundingMode(models.RoundHalfUp))
+
+	_, err := c.Trading.PlaceOrder(context.Background(), &models.PlaceOrderRequest{
+		Symbol:            "BTC-USD",
+		Side:              "sell",
+		Type:              "market",
+		MarketOrderConfig: &models.MarketOrderConfig{AssetQuantity: models.MustParseDecimal("0.000096")},
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+
+	var body struct {
+		MarketOrderConfig models.MarketOrderConfig `json:"market_order_config"`
+	}
+	json.Unmarshal([]byte(server.orders[0]), &body)
+	if got := body.MarketOrderConfig.AssetQuantity.String(); got != "0.00010" {
+		t.Errorf("asset_quantity = %s, want 0.00010", got)
+	}
+}
+
+func TestPlaceOrder_OrderSizeLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity string
+		want     string
+	}{
+		{"below minimum", "0.00005", "BTC-USD: market_order_config.asset_quantity 0.00005 is below the minimum order size 0.0001"},
+		{"rounds to zero", "0.000001", "BTC-USD: market_order_config.asset_quantity 0.00000 is below the minimum order size 0.0001"},
+		{"above maximum", "25", "BTC-USD: market_order_config.asset_quantity 25.00000 is above the maximum order size 20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &instrumentServer{}
+			c := newTestClient(t, server.handle)
+
+			_, err := c.Trading.PlaceOrder(context.Background(), &models.PlaceOrderRequest{
+				Symbol:            "BTC-USD",
+				Side:              "buy",
+				Type:              "market",
+				MarketOrderConfig: &models.MarketOrderConfig{AssetQuantity: models.MustParseDecimal(tt.quantity)},
+			})
+
+			var sizeErr *errors.OrderSizeError
+			if !stderrors.As(err, &sizeErr) {
+				t.Fatalf("PlaceOrder() error = %v, want *errors.OrderSizeError", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("error = %q, want %q", err.Error(), tt.want)
+			}
+			if sizeErr.Field != "MarketOrderConfig.AssetQuantity" {
+				t.Errorf("Field = %q, want MarketOrderConfig.AssetQuantity", sizeErr.Field)
+			}
+			if !stderrors.Is(err, errors.ErrValidation) {
+				t.Error("errors.Is(err, ErrValidation) = false, want true")
+			}
+			if len(server.orders) != 0 {
+				t.Errorf("orders sent = %d, want 0", len(server.orders))
+			}
+		})
+	}
+}
+
+func TestInstrumentRegistry_UnknownSymbol(t *testing.T) {
+	server := &instrumentServer{}
+	c := newTestClient(t, server.handle)
+
+	_, err := c.Instruments.Get(context.Background(), "DOGE-EUR")
+	if !stderrors.Is(err, errors.ErrValidation) {
+		t.Errorf("Get() error = %v, want ErrValidation", err)
+	}
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Request is an API call as seen by middleware. Middleware may modify it
+// before passing it on; the body is marshaled and signed afterwards.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+
+	// Body is the value marshaled as the JSON request body, or nil
+	Body interface{}
+
+	// Header holds extra headers sent with every attempt. Authentication
+	// headers always take precedence.
+	Header http.Header
+
+	// Result is the value the response body is decoded into, or nil. A
+	// middleware that answers a request without calling next is
+	// responsible for populating it.
+	Result interface{}
+}
+
+// Response is the outcome of an API call as seen by middleware
+type Response struct {
+	StatusCode int
+	Header     http.Header
+
+	// Body is the raw response body
+	Body []byte
+
+	// Result is the decoded response, the same value as Request.Result
+	Result interface{}
+}
+
+// RoundTripFunc performs an API call, including retries and decoding. The
+// returned response may be non-nil alongside an API error.
+type RoundTripFunc func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a RoundTripFunc with additional behavior
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware appends middleware to the client. The first middleware
+// added is the outermost one and sees each request first.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// chain wraps a round trip with the client's middleware
+func (c *Client) chain(rt RoundTripFunc) RoundTripFunc {
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+	return rt
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestWithMiddleware_Order(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next(ctx, req)
+				calls = append(calls, name+" after")
+				return resp, err
+			}
+		}
+	}
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}, WithMiddleware(record("outer")), WithMiddleware(record("inner")))
+
+	if _, err := c.Account.GetAccountDetails(context.Background()); err != nil {
+		t.Fatalf("GetAccountDetails() error = %v", err)
+	}
+
+	want := []string{"outer before", "inner before", "inner after", "outer after"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("calls[%d] = %q, want %q", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestWithMiddleware_SeesTypedRequestAndResponse(t *testing.T) {
+	var gotHeader string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Audit-ID")
+		w.Write([]byte(`{"account_number":"ACC1","status":"active"}`))
+	}, WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.Method != "GET" || req.Path != "/api/v1/crypto/trading/accounts/" {
+				t.Errorf("request = %s %s", req.Method, req.Path)
+			}
+			req.Header = http.Header{"X-Audit-ID": []string{"audit-1"}}
+
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, err
+			}
+			if resp.StatusCode != http.StatusOK || resp.Result == nil {
+				t.Errorf("response = %+v, want decoded 200", resp)
+			}
+			return resp, nil
+		}
+	}))
+
+	account, err := c.Account.GetAccountDetails(context.Background())
+	if err != nil {
+		t.Fatalf("GetAccountDetails() error = %v", err)
+	}
+	if account.AccountNumber != "ACC1" {
+		t.Errorf("AccountNumber = %q, want %q", account.AccountNumber, "ACC1")
+	}
+	if gotHeader != "audit-1" {
+		t.Errorf("X-Audit-ID = %q, want %q", gotHeader, "audit-1")
+	}
+}
+
+func TestWithMiddleware_FaultInjection(t *testing.T) {
+	injected := errors.New("injected fault")
+	called := false
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}, WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			return nil, injected
+		}
+	}))
+
+	_, err := c.Account.GetAccountDetails(context.Background())
+	if !errors.Is(err, injected) {
+		t.Errorf("GetAccountDetails() error = %v, want %v", err, injected)
+	}
+	if called {
+		t.Error("server was called despite middleware short-circuit")
+	}
+}
@@ -0,0 +1,471 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// Defaults for OrderWatcherConfig
+const (
+	defaultOrderMinInterval = time.Second
+	defaultOrderMaxInterval = 15 * time.Second
+)
+
+// orderWatchOverlap is how far before the latest update already seen each
+// poll starts, so that updates the server records out of order are not
+// missed. Orders seen again without changes produce no events.
+const orderWatchOverlap = time.Second
+
+// OrderWatcherConfig configures an OrderWatcher. Zero fields use defaults.
+type OrderWatcherConfig struct {
+	// MinInterval is the delay between polls while watched orders are
+	// changing. Defaults to 1s.
+	MinInterval time.Duration
+
+	// MaxInterval caps the delay between polls, which doubles after every
+	// poll that finds no changes. Defaults to 15s.
+	MaxInterval time.Duration
+
+	// OnEvent is called for every event, in order, on the goroutine running
+	// Run and without the watcher's lock held
+	OnEvent func(OrderEvent)
+}
+
+// OrderEventType identifies the kind of an OrderEvent
+type OrderEventType int
+
+const (
+	// OrderAccepted reports an order seen for the first time in any state
+	// but failed
+	OrderAccepted OrderEventType = iota
+	// OrderPartiallyFilled reports new executions on an order that is
+	// still open
+	OrderPartiallyFilled
+	// OrderFilled reports an order that has completely filled
+	OrderFilled
+	// OrderCanceled reports an order that was canceled, possibly after
+	// filling in part
+	OrderCanceled
+	// OrderFailed reports an order the exchange rejected
+	OrderFailed
+	// OrderWatchError reports a failed poll
+	OrderWatchError
+)
+
+func (t OrderEventType) String() string {
+	switch t {
+	case OrderAccepted:
+		return "accepted"
+	case OrderPartiallyFilled:
+		return "partially_filled"
+	case OrderFilled:
+		return "filled"
+	case OrderCanceled:
+		return "canceled"
+	case OrderFailed:
+		return "failed"
+	case OrderWatchError:
+		return "error"
+	}
+	return fmt.Sprintf("OrderEventType(%d)", int(t))
+}
+
+// OrderEvent reports a change to a watched order
+type OrderEvent struct {
+	Type  OrderEventType
+	Order models.Order
+
+	// Previous is the order's state before the change, and empty the first
+	// time the order is seen
+	Previous models.OrderState
+
+	// Executions are the order's executions that are new since its last
+	// event
+	Executions []models.Execution
+
+	// Err is set for OrderWatchError events
+	Err error
+}
+
+// OrderWatcher follows many orders through their lifecycle. Rather than
+// fetching each order, it polls GetOrders for every order updated since the
+// last poll, polling quickly while orders are changing and backing off
+// while they are not. Orders are dropped from polling once they reach a
+// terminal state.
+//
+// Call Run to start polling.
+type OrderWatcher struct {
+	trading *TradingService
+	config  OrderWatcherConfig
+	wake    chan struct{}
+
+	mu      sync.Mutex
+	orders  map[string]*watchedOrder
+	queued  []OrderEvent
+	since   time.Time
+	running bool
+}
+
+// watchedOrder is the last known snapshot of an order and the callers
+// waiting on it
+type watchedOrder struct {
+	order      models.Order
+	seen       bool
+	executions int
+	waiters    []*orderWaiter
+}
+
+// orderWaiter is a WaitForState call in progress
+type orderWaiter struct {
+	states []models.OrderState
+	done   chan struct{}
+	order  models.Order
+	err    error
+}
+
+// NewOrderWatcher creates a watcher that polls orders with this service
+func (s *TradingService) NewOrderWatcher(config OrderWatcherConfig) *OrderWatcher {
+	if config.MinInterval <= 0 {
+		config.MinInterval = defaultOrderMinInterval
+	}
+	if config.MaxInterval <= 0 {
+		config.MaxInterval = defaultOrderMaxInterval
+	}
+	config.MaxInterval = max(config.MaxInterval, config.MinInterval)
+
+	return &OrderWatcher{
+		trading: s,
+		config:  config,
+		wake:    make(chan struct{}, 1),
+		orders:  make(map[string]*watchedOrder),
+	}
+}
+
+// Watch starts following an order, typically the one returned by
+// PlaceOrder. The order is compared with its last known snapshot, so
+// watching it again only reports what has changed.
+func (w *OrderWatcher) Watch(order *models.Order) {
+	w.mu.Lock()
+	w.observe(*order)
+	w.lowerSince(order.UpdatedAt)
+	w.mu.Unlock()
+	w.poke()
+}
+
+// Unwatch stops following an order and forgets it. Callers waiting on it
+// keep waiting until their contexts are done.
+func (w *OrderWatcher) Unwatch(orderID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.orders, orderID)
+}
+
+// Order returns the last known snapshot of a watched order
+func (w *OrderWatcher) Order(orderID string) (models.Order, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entry, ok := w.orders[orderID]
+	if !ok || !entry.seen {
+		return models.Order{}, false
+	}
+	return entry.order, true
+}
+
+// WaitForState blocks until the order is in one of states and returns it.
+// If the order reaches a different terminal state it is returned with an
+// *errors.OrderStateError. An order not yet watched is fetched with
+// GetOrder and watched. Run must be running for the order to progress.
+func (w *OrderWatcher) WaitForState(ctx context.Context, orderID string, states ...models.OrderState) (*models.Order, error) {
+	if len(states) == 0 {
+		return nil, fmt.Errorf("%w: no order states to wait for", errors.ErrValidation)
+	}
+	waiter := &orderWaiter{states: states, done: make(chan struct{})}
+
+	w.mu.Lock()
+	entry, watched := w.orders[orderID]
+	if watched && entry.seen && waiter.check(orderID, entry.order) {
+		w.mu.Unlock()
+		return &waiter.order, waiter.err
+	}
+	if !watched {
+		entry = &watchedOrder{}
+		w.orders[orderID] = entry
+	}
+	entry.waiters = append(entry.waiters, waiter)
+	w.mu.Unlock()
+
+	if !watched {
+		order, err := w.trading.GetOrder(ctx, orderID)
+		if err != nil {
+			w.removeWaiter(orderID, waiter)
+			return nil, err
+		}
+		w.Watch(order)
+	}
+
+	select {
+	case <-ctx.Done():
+		w.removeWaiter(orderID, waiter)
+		return nil, ctx.Err()
+	case <-waiter.done:
+		return &waiter.order, waiter.err
+	}
+}
+
+// WaitForFill blocks until the order has completely filled. An order
+// canceled or failed first is returned with an *errors.OrderStateError.
+func (w *OrderWatcher) WaitForFill(ctx context.Context, orderID string) (*models.Order, error) {
+	return w.WaitForState(ctx, orderID, models.OrderStateFilled)
+}
+
+// Run polls until ctx is done. A watcher can only be run once.
+func (w *OrderWatcher) Run(ctx context.Context) error {
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		return fmt.Errorf("order watcher already started")
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	interval := w.config.MinInterval
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	// next is when the timer fires, and zero while idle
+	next := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.wake:
+			// A newly watched order may already have events to deliver,
+			// and polling returns to its fastest rate. Watching more orders
+			// only ever brings the next poll forward.
+			w.deliver()
+			interval = w.config.MinInterval
+			if soonest := time.Now().Add(interval); next.IsZero() || next.After(soonest) {
+				next = soonest
+				timer.Reset(interval)
+			}
+			continue
+		case <-timer.C:
+		}
+
+		active, changed := w.poll(ctx)
+		w.deliver()
+
+		switch {
+		case !active:
+			// Nothing to poll for until an order is watched
+			next = time.Time{}
+			continue
+		case changed:
+			interval = w.config.MinInterval
+		default:
+			interval = min(2*interval, w.config.MaxInterval)
+		}
+		next = time.Now().Add(interval)
+		timer.Reset(interval)
+	}
+}
+
+// poke wakes Run without blocking
+func (w *OrderWatcher) poke() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// poll fetches orders updated since the last poll. It reports whether any
+// watched order is still open, and whether any changed.
+func (w *OrderWatcher) poll(ctx context.Context) (active, changed bool) {
+	w.mu.Lock()
+	since := w.since
+	active = w.active()
+	w.mu.Unlock()
+	if !active {
+		return false, false
+	}
+
+	start := since.Add(-orderWatchOverlap)
+	filter := &models.OrdersFilter{UpdatedAtStart: &start}
+	latest := since
+	var updates []models.Order
+	for order, err := range w.trading.AllOrders(ctx, filter) {
+		if err != nil {
+			if ctx.Err() == nil {
+				w.mu.Lock()
+				w.queued = append(w.queued, OrderEvent{Type: OrderWatchError, Err: err})
+				w.mu.Unlock()
+			}
+			return true, false
+		}
+		updates = append(updates, order)
+		if order.UpdatedAt.After(latest) {
+			latest = order.UpdatedAt
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	queued := len(w.queued)
+	for _, order := range updates {
+		if _, ok := w.orders[order.ID]; ok {
+			w.observe(order)
+		}
+	}
+	if latest.After(w.since) {
+		w.since = latest
+	}
+	return w.active(), len(w.queued) > queued
+}
+
+// deliver calls OnEvent for queued events
+func (w *OrderWatcher) deliver() {
+	w.mu.Lock()
+	events := w.queued
+	w.queued = nil
+	w.mu.Unlock()
+
+	if w.config.OnEvent == nil {
+		return
+	}
+	for _, event := range events {
+		w.config.OnEvent(event)
+	}
+}
+
+// observe records a snapshot of an order, queues events for what changed
+// and releases waiters it satisfies. The lock must be held.
+func (w *OrderWatcher) observe(order models.Order) {
+	entry, ok := w.orders[order.ID]
+	if !ok {
+		entry = &watchedOrder{}
+		w.orders[order.ID] = entry
+	}
+
+	// Ignore snapshots older than the one already seen
+	if entry.seen && order.UpdatedAt.Before(entry.order.UpdatedAt) {
+		return
+	}
+
+	w.queued = append(w.queued, orderEvents(entry, order)...)
+	entry.order = order
+	entry.seen = true
+	entry.executions = max(entry.executions, len(order.Executions))
+
+	entry.waiters = slices.DeleteFunc(entry.waiters, func(waiter *orderWaiter) bool {
+		if waiter.check(order.ID, order) {
+			close(waiter.done)
+			return true
+		}
+		return false
+	})
+}
+
+// orderEvents returns the events for a change from entry's snapshot to
+// order
+func orderEvents(entry *watchedOrder, order models.Order) []OrderEvent {
+	var previous models.OrderState
+	if entry.seen {
+		previous = entry.order.State
+	}
+	var executions []models.Execution
+	if len(order.Executions) > entry.executions {
+		executions = slices.Clone(order.Executions[entry.executions:])
+	}
+	event := func(t OrderEventType, executions []models.Execution) OrderEvent {
+		return OrderEvent{Type: t, Order: order, Previous: previous, Executions: executions}
+	}
+
+	var events []OrderEvent
+	if !entry.seen && order.State != models.OrderStateFailed {
+		events = append(events, event(OrderAccepted, nil))
+	}
+
+	switch order.State {
+	case models.OrderStatePartiallyFilled:
+		if len(executions) > 0 || previous != order.State {
+			events = append(events, event(OrderPartiallyFilled, executions))
+		}
+	case models.OrderStateFilled:
+		if previous != order.State {
+			events = append(events, event(OrderFilled, executions))
+		}
+	case models.OrderStateCanceled:
+		if previous != order.State {
+			events = append(events, event(OrderCanceled, executions))
+		}
+	case models.OrderStateFailed:
+		if previous != order.State {
+			events = append(events, event(OrderFailed, executions))
+		}
+	}
+	return events
+}
+
+// active reports whether any watched order may still change. The lock must
+// be held.
+func (w *OrderWatcher) active() bool {
+	for _, entry := range w.orders {
+		if entry.seen && !entry.order.State.IsTerminal() {
+			return true
+		}
+	}
+	return false
+}
+
+// lowerSince moves the start of the next poll back to t, or to the
+// reconcile window before the server's estimated time if t is unknown. The
+// lock must be held.
+func (w *OrderWatcher) lowerSince(t time.Time) {
+	if t.IsZero() {
+		t = w.trading.client.auth.Now().Add(-reconcileWindow)
+	}
+	if w.since.IsZero() || t.Before(w.since) {
+		w.since = t
+	}
+}
+
+// removeWaiter abandons a wait, forgetting the order if it was only being
+// fetched for this waiter
+func (w *OrderWatcher) removeWaiter(orderID string, waiter *orderWaiter) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entry, ok := w.orders[orderID]
+	if !ok {
+		return
+	}
+	entry.waiters = slices.DeleteFunc(entry.waiters, func(other *orderWaiter) bool {
+		return other == waiter
+	})
+	if !entry.seen && len(entry.waiters) == 0 {
+		delete(w.orders, orderID)
+	}
+}
+
+// check reports whether the order settles the wait, recording the result
+// if it does
+func (waiter *orderWaiter) check(orderID string, order models.Order) bool {
+	switch {
+	case slices.Contains(waiter.states, order.State):
+		waiter.order = order
+		return true
+	case order.State.IsTerminal():
+		waiter.order = order
+		waiter.err = &errors.OrderStateError{ID: orderID, State: order.State, Want: waiter.states}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// orderServer serves orders, filtering the list by updated_at_start, state
+// and symbol, and cancels them
+type orderServer struct {
+	mu      sync.Mutex
+	orders  map[string]models.Order
+	starts  []string
+	gets    int
+	cancels []string
+
+	// uncancelable orders fail to cancel
+	uncancelable map[string]bool
+}
+
+func (s *orderServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rest, ok := strings.CutPrefix(r.URL.Path, ordersPath); ok && rest != "" {
+		id, cancel := strings.CutSuffix(strings.TrimSuffix(rest, "/"), "/cancel")
+		order, found := s.orders[id]
+		switch {
+		case !found:
+			w.WriteHeader(http.StatusNotFound)
+		case cancel:
+			s.cancels = append(s.cancels, id)
+			if s.uncancelable[id] {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"type":"validation_error","errors":[{"attr":"non_field_errors","detail":"order is not open"}]}`))
+				return
+			}
+			order.State = models.OrderStateCanceled
+			order.UpdatedAt = order.UpdatedAt.Add(time.Second)
+			s.orders[id] = order
+		default:
+			s.gets++
+			json.NewEncoder(w).Encode(order)
+		}
+		return
+	}
+
+	query := r.URL.Query()
+	start := query.Get("updated_at_start")
+	s.starts = append(s.starts, start)
+	since, _ := models.ParseTime(start)
+	var resp models.OrdersResponse
+	for _, order := range s.orders {
+		if order.UpdatedAt.Before(since) {
+			continue
+		}
+		if state := query.Get("state"); state != "" && string(order.State) != state {
+			continue
+		}
+		if symbol := query.Get("symbol"); symbol != "" && order.Symbol != symbol {
+			continue
+		}
+		resp.Results = append(resp.Results, order)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// update changes an order and moves its updated time forward
+func (s *orderServer) update(id string, change func(*models.Order)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order := s.orders[id]
+	change(&order)
+	order.UpdatedAt = order.UpdatedAt.Add(time.Second)
+	s.orders[id] = order
+}
+
+func execution(quantity string) models.Execution {
+	return models.Execution{Quantity: models.MustParseDecimal(quantity), EffectivePrice: models.NewDecimalFromInt(50000)}
+}
+
+func TestOrderWatcher_Events(t *testing.T) {
+	placed := time.Now().Truncate(time.Second)
+	server := &orderServer{orders: map[string]models.Order{
+		"order-1": {ID: "order-1", State: models.OrderStateOpen, UpdatedAt: placed},
+		"other":   {ID: "other", State: models.OrderStateOpen, UpdatedAt: placed},
+	}}
+	c := newTestClient(t, server.handle)
+
+	events := make(chan OrderEvent, 10)
+	watcher := c.Trading.NewOrderWatcher(OrderWatcherConfig{
+		MinInterval: 5 * time.Millisecond,
+		MaxInterval: 10 * time.Millisecond,
+		OnEvent:     func(e OrderEvent) { events <- e },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx)
+
+	order := server.orders["order-1"]
+	watcher.Watch(&order)
+	next := func() OrderEvent {
+		t.Helper()
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for an event")
+			return OrderEvent{}
+		}
+	}
+
+	if e := next(); e.Type != OrderAccepted || e.Order.ID != "order-1" || e.Previous != "" {
+		t.Errorf("event = %v %+v, want accepted", e.Type, e)
+	}
+
+	server.update("order-1", func(o *models.Order) {
+		o.State = models.OrderStatePartiallyFilled
+		o.Executions = []models.Execution{execution("0.1")}
+	})
+	e := next()
+	if e.Type != OrderPartiallyFilled || e.Previous != models.OrderStateOpen || len(e.Executions) != 1 {
+		t.Errorf("event = %v %+v, want partially filled with one execution", e.Type, e)
+	}
+
+	server.update("order-1", func(o *models.Order) {
+		o.State = models.OrderStateFilled
+		o.Executions = append(o.Executions, execution("0.2"))
+	})
+	e = next()
+	if e.Type != OrderFilled || len(e.Executions) != 1 || e.Executions[0].Quantity.String() != "0.2" {
+		t.Errorf("event = %v %+v, want filled with only the new execution", e.Type, e)
+	}
+
+	// Changes to unwatched orders produce no events
+	server.update("other", func(o *models.Order) { o.State = models.OrderStateCanceled })
+	select {
+	case e := <-events:
+		t.Errorf("unexpected event %v %+v", e.Type, e)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.starts) == 0 || server.starts[0] != models.FormatTime(placed.Add(-orderWatchOverlap)) {
+		t.Errorf("updated_at_start = %v, want polling from the order's update time", server.starts)
+	}
+}
+
+func TestOrderWatcher_WaitForFill(t *testing.T) {
+	server := &orderServer{orders: map[string]models.Order{
+		"order-1": {ID: "order-1", State: models.OrderStateOpen, UpdatedAt: time.Now()},
+		"order-2": {ID: "order-2", State: models.OrderStateOpen, UpdatedAt: time.Now()},
+	}}
+	c := newTestClient(t, server.handle)
+
+	watcher := c.Trading.NewOrderWatcher(OrderWatcherConfig{MinInterval: 5 * time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go watcher.Run(ctx)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		server.update("order-1", func(o *models.Order) { o.State = models.OrderStateFilled })
+		server.update("order-2", func(o *models.Order) { o.State = models.OrderStateCanceled })
+	}()
+
+	// Orders not yet watched are fetched first
+	order, err := watcher.WaitForFill(ctx, "order-1")
+	if err != nil || order.State != models.OrderStateFilled {
+		t.Fatalf("WaitForFill() = %+v, %v, want the filled order", order, err)
+	}
+
+	order, err = watcher.WaitForFill(ctx, "order-2")
+	var stateErr *errors.OrderStateError
+	if !stderrors.As(err, &stateErr) || stateErr.State != models.OrderStateCanceled || order.State != models.OrderStateCanceled {
+		t.Errorf("WaitForFill() = %+v, %v, want an OrderStateError for the canceled order", order, err)
+	}
+
+	// A settled order is answered from its snapshot
+	if _, err := watcher.WaitForState(ctx, "order-1", models.OrderStateFilled, models.OrderStateCanceled); err != nil {
+		t.Errorf("WaitForState() error = %v", err)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.gets != 2 {
+		t.Errorf("GetOrder calls = %d, want 2", server.gets)
+	}
+}
+
+func TestOrderWatcher_WaitForStateCanceled(t *testing.T) {
+	server := &orderServer{orders: map[string]models.Order{
+		"order-1": {ID: "order-1", State: models.OrderStateOpen, UpdatedAt: time.Now()},
+	}}
+	c := newTestClient(t, server.handle)
+	watcher := c.Trading.NewOrderWatcher(OrderWatcherConfig{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := watcher.WaitForFill(ctx, "order-1"); err != context.DeadlineExceeded {
+		t.Errorf("WaitForFill() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if _, err := watcher.WaitForState(ctx, "order-1"); !stderrors.Is(err, errors.ErrValidation) {
+		t.Errorf("WaitForState() without states error = %v, want ErrValidation", err)
+	}
+}
+
+func TestOrderWatcher_WatchDoesNotDelayPolling(t *testing.T) {
+	order := models.Order{ID: "order-1", State: models.OrderStateOpen, UpdatedAt: time.Now()}
+	server := &orderServer{orders: map[string]models.Order{"order-1": order}}
+	c := newTestClient(t, server.handle)
+
+	watcher := c.Trading.NewOrderWatcher(OrderWatcherConfig{MinInterval: 20 * time.Millisecond, MaxInterval: 20 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx)
+
+	// Watching more often than MinInterval must not push back a due poll
+	for deadline := time.Now().Add(300 * time.Millisecond); time.Now().Before(deadline); {
+		watcher.Watch(&order)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.starts) < 5 {
+		t.Errorf("polls = %d in 300ms at a 20ms interval, want at least 5", len(server.starts))
+	}
+}
+
+func TestOrderWatcher_UnknownUpdateTimeUsesServerClock(t *testing.T) {
+	server := &orderServer{orders: map[string]models.Order{}}
+
+	// The local clock runs ten minutes ahead of the server's
+	serverOffset := -10 * time.Minute
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(serverOffset).UTC().Format(http.TimeFormat))
+		server.handle(w, r)
+	})
+
+	// Measure the skew from an earlier response
+	if _, err := c.Trading.GetOrders(context.Background(), nil); err != nil {
+		t.Fatalf("GetOrders() error = %v", err)
+	}
+
+	watcher := c.Trading.NewOrderWatcher(OrderWatcherConfig{MinInterval: 5 * time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go watcher.Run(ctx)
+	watcher.Watch(&models.Order{ID: "order-1", State: models.OrderStateOpen})
+
+	var polled []string
+	for ctx.Err() == nil && len(polled) < 2 {
+		time.Sleep(5 * time.Millisecond)
+		server.mu.Lock()
+		polled = append([]string(nil), server.starts...)
+		server.mu.Unlock()
+	}
+	if len(polled) < 2 {
+		t.Fatal("timed out waiting for a poll")
+	}
+	since, err := models.ParseTime(polled[1])
+	if err != nil || since.After(time.Now().Add(serverOffset)) {
+		t.Errorf("updated_at_start = %s, want it before the server's time", polled[1])
+	}
+}
@@ -2,24 +2,43 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"iter"
 	"net/url"
 	"strings"
 
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
 )
 
+// Endpoints recorded in pagination checkpoints
+const (
+	EndpointTradingPairs = "trading_pairs"
+	EndpointHoldings     = "holdings"
+	EndpointOrders       = "orders"
+)
+
 // PaginationOptions provides pagination control
 type PaginationOptions struct {
 	Cursor string
 	Limit  int
 }
 
-// Paginator helps iterate through paginated results
+// Paginator helps iterate through paginated results. The first call to Next
+// fetches the first page.
 type Paginator[T any] struct {
-	client   *Client
-	nextURL  string
-	prevURL  string
-	fetcher  func(ctx context.Context, cursor string) (*PaginatedResponse[T], error)
+	client     *Client
+	nextCursor string
+	prevCursor string
+	started    bool
+	pages      int
+	fetcher    func(ctx context.Context, cursor string) (*PaginatedResponse[T], error)
+
+	// checkpoint holds the endpoint and filter, see Checkpoint
+	checkpoint Checkpoint
+
+	// prefetchDepth enables background fetching, see WithPrefetch
+	prefetchDepth int
+	prefetch      *prefetcher[T]
 }
 
 // PaginatedResponse is a generic wrapper for paginated responses
@@ -29,14 +48,71 @@ type PaginatedResponse[T any] struct {
 	Results  []T    `json:"results"`
 }
 
-// HasNext returns true if there are more pages
+// Checkpoint is a serializable record of a paginator's position. It can be
+// saved, for example as JSON, and passed to one of the Resume constructors
+// to continue from the next page after a restart.
+type Checkpoint struct {
+	// Endpoint is the paginated endpoint, such as EndpointOrders
+	Endpoint string `json:"endpoint"`
+
+	// Filter, Symbols and AssetCodes hold the endpoint's filter
+	Filter     *models.OrdersFilter `json:"filter,omitempty"`
+	Symbols    []string             `json:"symbols,omitempty"`
+	AssetCodes []string             `json:"asset_codes,omitempty"`
+
+	// Cursor is the cursor of the next page, empty before the first page
+	// and after the last
+	Cursor string `json:"cursor,omitempty"`
+	// Started is set once the first page has been fetched
+	Started bool `json:"started"`
+	// Pages is the number of pages fetched so far
+	Pages int `json:"pages"`
+}
+
+// Done reports whether the checkpoint is past the last page
+func (cp Checkpoint) Done() bool {
+	return cp.Started && cp.Cursor == ""
+}
+
+// HasNext returns true if there are more pages, including the first page of
+// a paginator that has not fetched anything yet
 func (p *Paginator[T]) HasNext() bool {
-	return p.nextURL != ""
+	return !p.started || p.nextCursor != ""
 }
 
 // HasPrevious returns true if there are previous pages
 func (p *Paginator[T]) HasPrevious() bool {
-	return p.prevURL != ""
+	return p.prevCursor != ""
+}
+
+// Pages returns the number of pages fetched so far
+func (p *Paginator[T]) Pages() int {
+	return p.pages
+}
+
+// Checkpoint returns the paginator's current position. Resuming from it
+// fetches the page after the last one returned by Next.
+func (p *Paginator[T]) Checkpoint() Checkpoint {
+	cp := p.checkpoint
+	if cp.Filter != nil {
+		filter := *cp.Filter
+		cp.Filter = &filter
+	}
+	cp.Symbols = append([]string(nil), cp.Symbols...)
+	cp.AssetCodes = append([]string(nil), cp.AssetCodes...)
+
+	cp.Cursor = p.nextCursor
+	cp.Started = p.started
+	cp.Pages = p.pages
+	return cp
+}
+
+// restore moves the paginator to the position recorded in cp
+func (p *Paginator[T]) restore(cp *Checkpoint) *Paginator[T] {
+	p.nextCursor = cp.Cursor
+	p.started = cp.Started
+	p.pages = cp.Pages
+	return p
 }
 
 // Next fetches the next page of results
@@ -45,14 +121,21 @@ func (p *Paginator[T]) Next(ctx context.Context) ([]T, error) {
 		return nil, nil
 	}
 
-	cursor := extractCursor(p.nextURL)
-	resp, err := p.fetcher(ctx, cursor)
+	var resp *PaginatedResponse[T]
+	var err error
+	if p.prefetchDepth > 0 {
+		resp, err = p.nextPrefetched(ctx)
+	} else {
+		resp, err = p.fetcher(ctx, p.nextCursor)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	p.nextURL = resp.Next
-	p.prevURL = resp.Previous
+	p.started = true
+	p.pages++
+	p.nextCursor = extractCursor(resp.Next)
+	p.prevCursor = extractCursor(resp.Previous)
 	return resp.Results, nil
 }
 
@@ -62,14 +145,19 @@ func (p *Paginator[T]) Previous(ctx context.Context) ([]T, error) {
 		return nil, nil
 	}
 
-	cursor := extractCursor(p.prevURL)
-	resp, err := p.fetcher(ctx, cursor)
+	// Pages fetched ahead no longer follow the current position
+	p.Close()
+
+	resp, err := p.fetcher(ctx, p.prevCursor)
 	if err != nil {
 		return nil, err
 	}
 
-	p.nextURL = resp.Next
-	p.prevURL = resp.Previous
+	if p.pages > 1 {
+		p.pages--
+	}
+	p.nextCursor = extractCursor(resp.Next)
+	p.prevCursor = extractCursor(resp.Previous)
 	return resp.Results, nil
 }
 
@@ -87,12 +175,13 @@ func extractCursor(urlStr string) string {
 	return u.Query().Get("cursor")
 }
 
-// GetAllPages fetches all pages of results
+// GetAllPages fetches all pages of results. Background fetching started
+// with WithPrefetch is stopped when it returns.
 func (p *Paginator[T]) GetAllPages(ctx context.Context) ([]T, error) {
+	defer p.Close()
+
 	var allResults []T
-	
-	// Get initial results
-	if p.nextURL != "" {
+	for p.HasNext() {
 		results, err := p.Next(ctx)
 		if err != nil {
 			return nil, err
@@ -100,24 +189,65 @@ func (p *Paginator[T]) GetAllPages(ctx context.Context) ([]T, error) {
 		allResults = append(allResults, results...)
 	}
 
-	// Get remaining pages
-	for p.HasNext() {
-		results, err := p.Next(ctx)
-		if err != nil {
-			return nil, err
+	return allResults, nil
+}
+
+// All returns an iterator over the remaining results, fetching pages lazily
+// as the loop advances. Breaking out of the loop stops fetching. A fetch
+// error, including cancellation of ctx, is yielded once and ends the loop.
+func (p *Paginator[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer p.Close()
+
+		for p.HasNext() {
+			if err := ctx.Err(); err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			results, err := p.Next(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, result := range results {
+				if !yield(result, nil) {
+					return
+				}
+			}
 		}
-		allResults = append(allResults, results...)
 	}
+}
 
-	return allResults, nil
+// AllTradingPairs returns an iterator over every trading pair, optionally
+// limited to the given symbols
+func (s *TradingService) AllTradingPairs(ctx context.Context, symbols ...string) iter.Seq2[models.TradingPair, error] {
+	return s.NewTradingPairsPaginator(symbols...).All(ctx)
+}
+
+// AllHoldings returns an iterator over every holding, optionally limited to
+// the given asset codes
+func (s *TradingService) AllHoldings(ctx context.Context, assetCodes ...string) iter.Seq2[models.Holding, error] {
+	return s.NewHoldingsPaginator(assetCodes...).All(ctx)
+}
+
+// AllOrders returns an iterator over every order matching filter, which may
+// be nil
+func (s *TradingService) AllOrders(ctx context.Context, filter *models.OrdersFilter) iter.Seq2[models.Order, error] {
+	return s.NewOrdersPaginator(filter).All(ctx)
 }
 
 // Helper methods for creating paginators
 
 // NewTradingPairsPaginator creates a paginator for trading pairs
 func (s *TradingService) NewTradingPairsPaginator(symbols ...string) *Paginator[models.TradingPair] {
+	symbols = append([]string(nil), symbols...)
 	return &Paginator[models.TradingPair]{
-		client: s.client,
+		client:     s.client,
+		checkpoint: Checkpoint{Endpoint: EndpointTradingPairs, Symbols: symbols},
 		fetcher: func(ctx context.Context, cursor string) (*PaginatedResponse[models.TradingPair], error) {
 			query := url.Values{}
 			for _, symbol := range symbols {
@@ -128,7 +258,7 @@ func (s *TradingService) NewTradingPairsPaginator(symbols ...string) *Paginator[
 			}
 
 			var result models.TradingPairsResponse
-			err := s.client.do(ctx, "GET", "/api/v1/crypto/trading/trading_pairs/", query, nil, &result)
+			err := s.client.do(ctx, "GET", tradingPairsPath, query, nil, &result)
 			if err != nil {
 				return nil, err
 			}
@@ -144,8 +274,10 @@ func (s *TradingService) NewTradingPairsPaginator(symbols ...string) *Paginator[
 
 // NewHoldingsPaginator creates a paginator for holdings
 func (s *TradingService) NewHoldingsPaginator(assetCodes ...string) *Paginator[models.Holding] {
+	assetCodes = append([]string(nil), assetCodes...)
 	return &Paginator[models.Holding]{
-		client: s.client,
+		client:     s.client,
+		checkpoint: Checkpoint{Endpoint: EndpointHoldings, AssetCodes: assetCodes},
 		fetcher: func(ctx context.Context, cursor string) (*PaginatedResponse[models.Holding], error) {
 			query := url.Values{}
 			for _, code := range assetCodes {
@@ -170,19 +302,23 @@ func (s *TradingService) NewHoldingsPaginator(assetCodes ...string) *Paginator[m
 	}
 }
 
-// NewOrdersPaginator creates a paginator for orders
+// NewOrdersPaginator creates a paginator for orders. The filter is copied,
+// so later changes to it do not affect the paginator.
 func (s *TradingService) NewOrdersPaginator(filter *models.OrdersFilter) *Paginator[models.Order] {
+	// Copy filter to avoid modifying the original
+	base := &models.OrdersFilter{}
+	if filter != nil {
+		*base = *filter
+	}
+
 	return &Paginator[models.Order]{
-		client: s.client,
+		client:     s.client,
+		checkpoint: Checkpoint{Endpoint: EndpointOrders, Filter: base},
 		fetcher: func(ctx context.Context, cursor string) (*PaginatedResponse[models.Order], error) {
-			// Copy filter to avoid modifying the original
-			localFilter := &models.OrdersFilter{}
-			if filter != nil {
-				*localFilter = *filter
-			}
+			localFilter := *base
 			localFilter.Cursor = cursor
 
-			resp, err := s.GetOrders(ctx, localFilter)
+			resp, err := s.GetOrders(ctx, &localFilter)
 			if err != nil {
 				return nil, err
 			}
@@ -194,4 +330,42 @@ func (s *TradingService) NewOrdersPaginator(filter *models.OrdersFilter) *Pagina
 			}, nil
 		},
 	}
-}
\ No newline at end of file
+}
+
+// ResumeTradingPairsPaginator creates a trading pairs paginator that
+// continues from a checkpoint
+func (s *TradingService) ResumeTradingPairsPaginator(cp *Checkpoint) (*Paginator[models.TradingPair], error) {
+	if err := cp.check(EndpointTradingPairs); err != nil {
+		return nil, err
+	}
+	return s.NewTradingPairsPaginator(cp.Symbols...).restore(cp), nil
+}
+
+// ResumeHoldingsPaginator creates a holdings paginator that continues from a
+// checkpoint
+func (s *TradingService) ResumeHoldingsPaginator(cp *Checkpoint) (*Paginator[models.Holding], error) {
+	if err := cp.check(EndpointHoldings); err != nil {
+		return nil, err
+	}
+	return s.NewHoldingsPaginator(cp.AssetCodes...).restore(cp), nil
+}
+
+// ResumeOrdersPaginator creates an orders paginator that continues from a
+// checkpoint, using the filter recorded in it
+func (s *TradingService) ResumeOrdersPaginator(cp *Checkpoint) (*Paginator[models.Order], error) {
+	if err := cp.check(EndpointOrders); err != nil {
+		return nil, err
+	}
+	return s.NewOrdersPaginator(cp.Filter).restore(cp), nil
+}
+
+// check verifies that the checkpoint was taken from a paginator for endpoint
+func (cp *Checkpoint) check(endpoint string) error {
+	if cp == nil {
+		return fmt.Errorf("checkpoint is nil")
+	}
+	if cp.Endpoint != endpoint {
+		return fmt.Errorf("checkpoint is for %q, not %q", cp.Endpoint, endpoint)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// pagedOrders serves orders in pages of two, linking pages by cursor, and
+// counts the pages fetched
+type pagedOrders struct {
+	mu      sync.Mutex
+	orders  []models.Order
+	fetches int
+}
+
+func newPagedOrders(n int) *pagedOrders {
+	p := &pagedOrders{}
+	for i := 0; i < n; i++ {
+		p.orders = append(p.orders, models.Order{ID: fmt.Sprintf("order-%d", i)})
+	}
+	return p
+}
+
+func (p *pagedOrders) handle(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fetches++
+
+	start := 0
+	fmt.Sscan(r.URL.Query().Get("cursor"), &start)
+	end := min(start+2, len(p.orders))
+
+	resp := models.OrdersResponse{Results: p.orders[start:end]}
+	if end < len(p.orders) {
+		resp.Next = fmt.Sprintf("https://example.com%s?cursor=%d", ordersPath, end)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestPaginator_GetAllPagesFromStart(t *testing.T) {
+	server := newPagedOrders(5)
+	c := newTestClient(t, server.handle)
+
+	orders, err := c.Trading.NewOrdersPaginator(nil).GetAllPages(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllPages() error = %v", err)
+	}
+	if len(orders) != 5 {
+		t.Errorf("len(orders) = %d, want 5", len(orders))
+	}
+	if server.fetches != 3 {
+		t.Errorf("fetches = %d, want 3", server.fetches)
+	}
+}
+
+func TestAllOrders(t *testing.T) {
+	server := newPagedOrders(5)
+	c := newTestClient(t, server.handle)
+
+	var ids []string
+	for order, err := range c.Trading.AllOrders(context.Background(), nil) {
+		if err != nil {
+			t.Fatalf("AllOrders() error = %v", err)
+		}
+		ids = append(ids, order.ID)
+	}
+
+	if len(ids) != 5 || ids[0] != "order-0" || ids[4] != "order-4" {
+		t.Errorf("ids = %v, want order-0 through order-4", ids)
+	}
+}
+
+func TestAllOrders_BreakStopsFetching(t *testing.T) {
+	server := newPagedOrders(10)
+	c := newTestClient(t, server.handle)
+
+	seen := 0
+	for _, err := range c.Trading.AllOrders(context.Background(), nil) {
+		if err != nil {
+			t.Fatalf("AllOrders() error = %v", err)
+		}
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+
+	if server.fetches != 2 {
+		t.Errorf("fetches = %d, want 2", server.fetches)
+	}
+}
+
+func TestAllOrders_ContextCanceled(t *testing.T) {
+	server := newPagedOrders(10)
+	c := newTestClient(t, server.handle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var gotErr error
+	seen := 0
+	for _, err := range c.Trading.AllOrders(ctx, nil) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		seen++
+		if seen == 2 {
+			cancel()
+		}
+	}
+
+	if gotErr != context.Canceled {
+		t.Errorf("error = %v, want %v", gotErr, context.Canceled)
+	}
+	if seen != 2 || server.fetches != 1 {
+		t.Errorf("seen = %d, fetches = %d, want 2 and 1", seen, server.fetches)
+	}
+}
+
+func TestResumeOrdersPaginator(t *testing.T) {
+	server := newPagedOrders(5)
+	c := newTestClient(t, server.handle)
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	paginator := c.Trading.NewOrdersPaginator(&models.OrdersFilter{Symbol: "BTC-USD", CreatedAtStart: &start})
+	if _, err := paginator.Next(ctx); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	// Save the checkpoint as a process would before exiting
+	data, err := json.Marshal(paginator.Checkpoint())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if cp.Pages != 1 || cp.Cursor != "2" || cp.Filter.Symbol != "BTC-USD" || !cp.Filter.CreatedAtStart.Equal(start) {
+		t.Errorf("checkpoint = %s", data)
+	}
+
+	resumed, err := c.Trading.ResumeOrdersPaginator(&cp)
+	if err != nil {
+		t.Fatalf("ResumeOrdersPaginator() error = %v", err)
+	}
+	rest, err := resumed.GetAllPages(ctx)
+	if err != nil {
+		t.Fatalf("GetAllPages() error = %v", err)
+	}
+	if len(rest) != 3 || rest[0].ID != "order-2" {
+		t.Errorf("resumed results = %v, want order-2 through order-4", rest)
+	}
+	if resumed.Pages() != 3 || !resumed.Checkpoint().Done() {
+		t.Errorf("Pages() = %d, Done() = %v, want 3 and true", resumed.Pages(), resumed.Checkpoint().Done())
+	}
+
+	if _, err := c.Trading.ResumeHoldingsPaginator(&cp); err == nil {
+		t.Error("ResumeHoldingsPaginator() with an orders checkpoint succeeded, want error")
+	}
+}
@@ -0,0 +1,103 @@
+package client
+
+import "context"
+
+// prefetcher fetches pages in the background ahead of Paginator.Next
+type prefetcher[T any] struct {
+	pages  chan prefetchedPage[T]
+	cancel context.CancelFunc
+}
+
+// prefetchedPage is the outcome of one background fetch
+type prefetchedPage[T any] struct {
+	resp *PaginatedResponse[T]
+	err  error
+}
+
+// WithPrefetch enables prefetch mode, in which pages after the current one
+// are fetched in the background while the caller processes results. Up to
+// depth pages are fetched ahead of Next. Background fetches go through the
+// client's shared rate limiter like any other request. They carry the values
+// of the context passed to the Next call that started them but not its
+// cancellation, so each call to Next may use its own short-lived context;
+// that context only bounds how long the call waits for a page.
+//
+// Call Close to stop background fetching when abandoning a paginator before
+// its last page. GetAllPages closes the paginator when it returns, and
+// iterators returned by All when the loop ends.
+func (p *Paginator[T]) WithPrefetch(depth int) *Paginator[T] {
+	p.Close()
+	p.prefetchDepth = max(depth, 0)
+	return p
+}
+
+// Close stops background fetching and discards pages fetched ahead. The
+// paginator can still be used; the next call to Next resumes from the
+// current position.
+func (p *Paginator[T]) Close() {
+	if p.prefetch == nil {
+		return
+	}
+	p.prefetch.cancel()
+
+	// Wait for the fetching goroutine to exit
+	for range p.prefetch.pages {
+	}
+	p.prefetch = nil
+}
+
+// nextPrefetched returns the next page from the background fetcher,
+// starting it if needed
+func (p *Paginator[T]) nextPrefetched(ctx context.Context) (*PaginatedResponse[T], error) {
+	if p.prefetch == nil {
+		p.startPrefetch(ctx)
+	}
+
+	select {
+	case page, ok := <-p.prefetch.pages:
+		if !ok {
+			// The fetcher stopped before reaching this page, so fetch it
+			// directly with the caller's context
+			p.Close()
+			return p.fetcher(ctx, p.nextCursor)
+		}
+		if page.err != nil {
+			// Stop so that the next call retries from the current position
+			p.Close()
+			return nil, page.err
+		}
+		return page.resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// startPrefetch starts fetching pages from the current position. The
+// fetches outlive ctx and are stopped only by Close.
+func (p *Paginator[T]) startPrefetch(ctx context.Context) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	// The goroutine holds one page while waiting to send, so the buffer
+	// is one short of the depth
+	pages := make(chan prefetchedPage[T], p.prefetchDepth-1)
+	p.prefetch = &prefetcher[T]{pages: pages, cancel: cancel}
+
+	go func(cursor string) {
+		defer close(pages)
+		for {
+			resp, err := p.fetcher(ctx, cursor)
+			select {
+			case pages <- prefetchedPage[T]{resp: resp, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			cursor = extractCursor(resp.Next)
+			if cursor == "" {
+				return
+			}
+		}
+	}(p.nextCursor)
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPaginator_PrefetchReturnsPagesInOrder(t *testing.T) {
+	server := newPagedOrders(9)
+	c := newTestClient(t, server.handle)
+
+	orders, err := c.Trading.NewOrdersPaginator(nil).WithPrefetch(2).GetAllPages(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllPages() error = %v", err)
+	}
+	if len(orders) != 9 {
+		t.Fatalf("len(orders) = %d, want 9", len(orders))
+	}
+	for i, order := range orders {
+		if want := fmt.Sprintf("order-%d", i); order.ID != want {
+			t.Errorf("orders[%d].ID = %q, want %q", i, order.ID, want)
+		}
+	}
+}
+
+func TestPaginator_PrefetchDepth(t *testing.T) {
+	server := newPagedOrders(10)
+	c := newTestClient(t, server.handle)
+	ctx := context.Background()
+
+	paginator := c.Trading.NewOrdersPaginator(nil).WithPrefetch(2)
+	if _, err := paginator.Next(ctx); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	// One page consumed and two fetched ahead
+	fetches := func() int {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return server.fetches
+	}
+	deadline := time.Now().Add(time.Second)
+	for fetches() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := fetches(); got != 3 {
+		t.Errorf("fetches = %d, want 3", got)
+	}
+
+	// Closing discards the buffered pages; Next continues from the position
+	paginator.Close()
+	orders, err := paginator.Next(ctx)
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if len(orders) == 0 || orders[0].ID != "order-2" {
+		t.Errorf("Next() after Close = %v, want order-2 first", orders)
+	}
+	paginator.Close()
+}
+
+func TestPaginator_PrefetchOutlivesCallContext(t *testing.T) {
+	server := newPagedOrders(10)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Keep a background fetch in flight when each call's context ends
+		time.Sleep(5 * time.Millisecond)
+		server.handle(w, r)
+	})
+
+	paginator := c.Trading.NewOrdersPaginator(nil).WithPrefetch(1)
+	defer paginator.Close()
+
+	var got int
+	for paginator.HasNext() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		orders, err := paginator.Next(ctx)
+		cancel()
+		if err != nil {
+			t.Fatalf("Next() after page %d error = %v", paginator.Pages(), err)
+		}
+		got += len(orders)
+	}
+	if got != 10 {
+		t.Errorf("orders = %d, want 10", got)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.fetches != 5 {
+		t.Errorf("fetches = %d, want 5 with no page fetched twice", server.fetches)
+	}
+}
+
+func TestPaginator_GetAllPagesStopsPrefetchOnError(t *testing.T) {
+	server := newPagedOrders(40)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		server.handle(w, r)
+		time.Sleep(5 * time.Millisecond)
+	})
+	fetches := func() int {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return server.fetches
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := c.Trading.NewOrdersPaginator(nil).WithPrefetch(2).GetAllPages(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("GetAllPages() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	before := fetches()
+	time.Sleep(50 * time.Millisecond)
+	if after := fetches(); after != before {
+		t.Errorf("fetches = %d, then %d after GetAllPages returned, want no more", before, after)
+	}
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// QuoteCache caches best bid/ask quotes per symbol so that callers asking
+// for the same symbols in quick succession share one request. Enable it
+// with WithQuoteCache.
+//
+// When GetBestBidAsk is called, fresh cached symbols are served from the
+// cache, symbols already being fetched by another call wait for that
+// request, and only the rest are fetched, together in one request.
+type QuoteCache struct {
+	marketData *MarketDataService
+
+	mu        sync.Mutex
+	ttl       time.Duration
+	symbolTTL map[string]time.Duration
+	entries   map[string]cachedQuote
+	inflight  map[string]*quoteFetch
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// cachedQuote is a quote and when it was fetched
+type cachedQuote struct {
+	quote   models.BestBidAskResult
+	fetched time.Time
+}
+
+// quoteFetch is a request in flight for a set of symbols
+type quoteFetch struct {
+	done    chan struct{}
+	results map[string]models.BestBidAskResult
+	err     error
+}
+
+func newQuoteCache(marketData *MarketDataService, ttl time.Duration) *QuoteCache {
+	return &QuoteCache{
+		marketData: marketData,
+		ttl:        ttl,
+		symbolTTL:  make(map[string]time.Duration),
+		entries:    make(map[string]cachedQuote),
+		inflight:   make(map[string]*quoteFetch),
+		now:        time.Now,
+	}
+}
+
+// SetTTL sets how long quotes for symbol are cached, overriding the TTL
+// passed to WithQuoteCache. A zero TTL disables caching for the symbol,
+// although concurrent requests for it are still coalesced.
+func (q *QuoteCache) SetTTL(symbol string, ttl time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.symbolTTL[strings.ToUpper(symbol)] = ttl
+}
+
+// Invalidate removes cached quotes for symbols, or every cached quote if
+// none are given
+func (q *QuoteCache) Invalidate(symbols ...string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(symbols) == 0 {
+		clear(q.entries)
+		return
+	}
+	for _, symbol := range symbols {
+		delete(q.entries, strings.ToUpper(symbol))
+	}
+}
+
+// get returns quotes for symbols, fetching only those that are neither
+// cached nor already being fetched
+func (q *QuoteCache) get(ctx context.Context, symbols []string) (*models.BestBidAskResponse, error) {
+	q.mu.Lock()
+	now := q.now()
+	found := make(map[string]models.BestBidAskResult, len(symbols))
+	waits := make(map[*quoteFetch]struct{})
+	var missing []string
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		if _, ok := found[symbol]; ok {
+			continue
+		}
+		if entry, ok := q.entries[symbol]; ok && now.Sub(entry.fetched) < q.ttlFor(symbol) {
+			found[symbol] = entry.quote
+			continue
+		}
+		if fetch, ok := q.inflight[symbol]; ok {
+			waits[fetch] = struct{}{}
+			continue
+		}
+		if !slices.Contains(missing, symbol) {
+			missing = append(missing, symbol)
+		}
+	}
+
+	if len(missing) > 0 {
+		fetch := &quoteFetch{done: make(chan struct{})}
+		for _, symbol := range missing {
+			q.inflight[symbol] = fetch
+		}
+		waits[fetch] = struct{}{}
+
+		// The request is shared, so it must not be canceled with this
+		// caller's context
+		go q.fetch(context.WithoutCancel(ctx), missing, fetch)
+	}
+	q.mu.Unlock()
+
+	for fetch := range waits {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-fetch.done:
+		}
+		if fetch.err != nil {
+			return nil, fetch.err
+		}
+		for symbol, quote := range fetch.results {
+			found[symbol] = quote
+		}
+	}
+
+	// Results follow the order symbols were asked for
+	resp := &models.BestBidAskResponse{}
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		if quote, ok := found[symbol]; ok {
+			resp.Results = append(resp.Results, quote)
+			delete(found, symbol)
+		}
+	}
+	return resp, nil
+}
+
+// fetch requests symbols, caches the results and releases waiting callers
+func (q *QuoteCache) fetch(ctx context.Context, symbols []string, fetch *quoteFetch) {
+	resp, err := q.marketData.fetchBestBidAsk(ctx, symbols)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, symbol := range symbols {
+		delete(q.inflight, symbol)
+	}
+	if err != nil {
+		fetch.err = err
+	} else {
+		fetch.results = q.storeLocked(resp.Results)
+	}
+	close(fetch.done)
+}
+
+// store caches quotes fetched without going through the cache
+func (q *QuoteCache) store(quotes []models.BestBidAskResult) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.storeLocked(quotes)
+}
+
+// storeLocked caches quotes and returns them by symbol. The lock must be
+// held.
+func (q *QuoteCache) storeLocked(quotes []models.BestBidAskResult) map[string]models.BestBidAskResult {
+	now := q.now()
+	bySymbol := make(map[string]models.BestBidAskResult, len(quotes))
+	for _, quote := range quotes {
+		symbol := strings.ToUpper(quote.Symbol)
+		bySymbol[symbol] = quote
+		q.entries[symbol] = cachedQuote{quote: quote, fetched: now}
+	}
+	return bySymbol
+}
+
+// ttlFor returns the TTL for symbol. The lock must be held.
+func (q *QuoteCache) ttlFor(symbol string) time.Duration {
+	if ttl, ok := q.symbolTTL[symbol]; ok {
+		return ttl
+	}
+	return q.ttl
+}
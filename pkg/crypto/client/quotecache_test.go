@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQuoteCache_ServesFreshQuotes(t *testing.T) {
+	server := &quoteServer{prices: map[string]string{"BTC-USD": "45000", "ETH-USD": "3000"}}
+	c := newTestClient(t, server.handle, WithQuoteCache(time.Second))
+
+	now := time.Now()
+	c.MarketData.Cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := c.MarketData.GetBestBidAsk(ctx, "BTC-USD"); err != nil {
+		t.Fatalf("GetBestBidAsk() error = %v", err)
+	}
+
+	// Only the uncached symbol is fetched, and results keep the order asked
+	resp, err := c.MarketData.GetBestBidAsk(ctx, "eth-usd", "btc-usd")
+	if err != nil {
+		t.Fatalf("GetBestBidAsk() error = %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Symbol != "ETH-USD" || resp.Results[1].Symbol != "BTC-USD" {
+		t.Errorf("results = %+v, want ETH-USD then BTC-USD", resp.Results)
+	}
+	if len(server.requests) != 2 || len(server.requests[1]) != 1 || server.requests[1][0] != "ETH-USD" {
+		t.Errorf("requests = %v, want BTC-USD then ETH-USD alone", server.requests)
+	}
+
+	// Expired quotes are fetched again
+	now = now.Add(2 * time.Second)
+	if _, err := c.MarketData.GetBestBidAsk(ctx, "BTC-USD", "ETH-USD"); err != nil {
+		t.Fatalf("GetBestBidAsk() error = %v", err)
+	}
+	if len(server.requests) != 3 || len(server.requests[2]) != 2 {
+		t.Errorf("requests = %v, want both symbols fetched together after expiry", server.requests)
+	}
+}
+
+func TestQuoteCache_SymbolTTL(t *testing.T) {
+	server := &quoteServer{prices: map[string]string{"BTC-USD": "45000"}}
+	c := newTestClient(t, server.handle, WithQuoteCache(time.Minute))
+	c.MarketData.Cache.SetTTL("btc-usd", 0)
+	ctx := context.Background()
+
+	c.MarketData.GetBestBidAsk(ctx, "BTC-USD")
+	c.MarketData.GetBestBidAsk(ctx, "BTC-USD")
+	if len(server.requests) != 2 {
+		t.Errorf("requests = %d, want 2 with caching disabled for the symbol", len(server.requests))
+	}
+}
+
+func TestQuoteCache_CoalescesConcurrentRequests(t *testing.T) {
+	server := &quoteServer{prices: map[string]string{"BTC-USD": "45000"}}
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		server.handle(w, r)
+	}, WithQuoteCache(time.Minute))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.MarketData.GetBestBidAsk(context.Background(), "BTC-USD")
+			if err == nil && len(resp.Results) != 1 {
+				t.Errorf("results = %+v, want BTC-USD", resp.Results)
+			}
+			errs <- err
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetBestBidAsk() error = %v", err)
+		}
+	}
+	if len(server.requests) != 1 {
+		t.Errorf("requests = %d, want 1", len(server.requests))
+	}
+}
+
+func TestQuoteCache_CanceledCallerDoesNotCancelFetch(t *testing.T) {
+	server := &quoteServer{prices: map[string]string{"BTC-USD": "45000"}}
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		server.handle(w, r)
+	}, WithQuoteCache(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.MarketData.GetBestBidAsk(ctx, "BTC-USD")
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("GetBestBidAsk() error = %v, want %v", err, context.Canceled)
+	}
+
+	// A second caller joins the request still in flight
+	close(release)
+	resp, err := c.MarketData.GetBestBidAsk(context.Background(), "BTC-USD")
+	if err != nil || len(resp.Results) != 1 {
+		t.Fatalf("GetBestBidAsk() = %v, %v", resp, err)
+	}
+	if len(server.requests) != 1 {
+		t.Errorf("requests = %d, want 1", len(server.requests))
+	}
+}
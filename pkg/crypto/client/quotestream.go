@@ -0,0 +1,416 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// Defaults for QuoteStreamConfig
+const (
+	defaultQuotePollInterval    = 2 * time.Second
+	defaultQuoteRequestsPerMin  = 30
+	defaultQuoteSymbolsPerReq   = 50
+	defaultQuoteBufferSize      = 16
+	defaultQuoteStaleMultiplier = 3
+)
+
+// QuoteStreamConfig configures a QuoteStream. Zero fields use defaults.
+type QuoteStreamConfig struct {
+	// PollInterval is how often quotes are polled. Defaults to 2s.
+	PollInterval time.Duration
+
+	// RequestsPerMinute is the share of the rate limit the stream may use.
+	// When polling every batch at PollInterval would exceed it, the stream
+	// polls less often. Defaults to 30, under a third of the API's 100 per
+	// minute; a negative value removes the budget.
+	RequestsPerMinute int
+
+	// MaxSymbolsPerRequest is the largest number of symbols fetched in one
+	// request. Larger symbol sets are split into batches. Defaults to 50.
+	MaxSymbolsPerRequest int
+
+	// StaleAfter is how long a symbol may go without a quote from the API
+	// before a QuoteStale event is emitted. Defaults to three poll intervals.
+	StaleAfter time.Duration
+
+	// BufferSize is the capacity of each subscription's channel. When a
+	// subscriber falls behind, the oldest events are dropped. Defaults to 16.
+	BufferSize int
+}
+
+// QuoteEventType identifies the kind of a QuoteEvent
+type QuoteEventType int
+
+const (
+	// QuoteUpdate carries a quote that differs from the last one delivered
+	QuoteUpdate QuoteEventType = iota
+	// QuoteStale reports that no quote has been received for a symbol for
+	// longer than StaleAfter. Quote holds the last known quote.
+	QuoteStale
+	// QuoteError reports a failed poll for the subscription's symbols
+	QuoteError
+)
+
+func (t QuoteEventType) String() string {
+	switch t {
+	case QuoteUpdate:
+		return "update"
+	case QuoteStale:
+		return "stale"
+	case QuoteError:
+		return "error"
+	}
+	return fmt.Sprintf("QuoteEventType(%d)", int(t))
+}
+
+// QuoteEvent is delivered to subscribers of a QuoteStream
+type QuoteEvent struct {
+	Type   QuoteEventType
+	Symbol string
+	Quote  models.BestBidAskResult
+
+	// Received is when Quote was last returned by the API
+	Received time.Time
+
+	// Err is set for QuoteError events
+	Err error
+}
+
+// QuoteStream polls best bid/ask quotes for the symbols its subscribers are
+// interested in and delivers changes over channels. All symbols are fetched
+// together, in as few requests as MaxSymbolsPerRequest allows, and quotes
+// that have not changed since the last poll are not delivered again.
+//
+// Call Run to start polling.
+type QuoteStream struct {
+	marketData *MarketDataService
+	config     QuoteStreamConfig
+
+	mu      sync.Mutex
+	subs    map[*Subscription]struct{}
+	quotes  map[string]*quoteState
+	running bool
+	closed  bool
+}
+
+// quoteState is the last quote seen for a symbol
+type quoteState struct {
+	quote    models.BestBidAskResult
+	received time.Time
+	stale    bool
+
+	// quoted is false until the API returns the symbol, in which case
+	// received is when polling for it started
+	quoted bool
+}
+
+// Subscription receives quote events for a set of symbols
+type Subscription struct {
+	// C delivers events. It is closed by Unsubscribe or when the stream
+	// stops running.
+	C <-chan QuoteEvent
+
+	stream  *QuoteStream
+	symbols []string
+	ch      chan QuoteEvent
+	closed  bool
+	dropped atomic.Int64
+}
+
+// NewQuoteStream creates a stream that polls quotes with this service
+func (s *MarketDataService) NewQuoteStream(config QuoteStreamConfig) *QuoteStream {
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultQuotePollInterval
+	}
+	if config.RequestsPerMinute == 0 {
+		config.RequestsPerMinute = defaultQuoteRequestsPerMin
+	}
+	if config.MaxSymbolsPerRequest <= 0 {
+		config.MaxSymbolsPerRequest = defaultQuoteSymbolsPerReq
+	}
+	if config.StaleAfter <= 0 {
+		config.StaleAfter = defaultQuoteStaleMultiplier * config.PollInterval
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultQuoteBufferSize
+	}
+
+	return &QuoteStream{
+		marketData: s,
+		config:     config,
+		subs:       make(map[*Subscription]struct{}),
+		quotes:     make(map[string]*quoteState),
+	}
+}
+
+// Subscribe returns a subscription to quotes for symbols. Symbols already
+// being polled are delivered their last known quote straight away, and new
+// symbols are included from the next poll. Subscribing to a stream that has
+// stopped returns a closed subscription.
+func (q *QuoteStream) Subscribe(symbols ...string) *Subscription {
+	ch := make(chan QuoteEvent, q.config.BufferSize)
+	sub := &Subscription{C: ch, stream: q, ch: ch}
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		if !slices.Contains(sub.symbols, symbol) {
+			sub.symbols = append(sub.symbols, symbol)
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		sub.closed = true
+		close(ch)
+		return sub
+	}
+	q.subs[sub] = struct{}{}
+
+	for _, symbol := range sub.symbols {
+		if state, ok := q.quotes[symbol]; ok && state.quoted {
+			sub.send(QuoteEvent{Type: QuoteUpdate, Symbol: symbol, Quote: state.quote, Received: state.received})
+		}
+	}
+	return sub
+}
+
+// Unsubscribe stops delivery and closes C. Symbols no other subscription
+// is interested in are no longer polled.
+func (sub *Subscription) Unsubscribe() {
+	q := sub.stream
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.subs, sub)
+	sub.close()
+}
+
+// Symbols returns the symbols the subscription receives quotes for
+func (sub *Subscription) Symbols() []string {
+	return slices.Clone(sub.symbols)
+}
+
+// Dropped returns the number of events discarded because the subscriber
+// was not keeping up
+func (sub *Subscription) Dropped() int64 {
+	return sub.dropped.Load()
+}
+
+// send delivers an event without blocking, dropping the oldest buffered
+// event if the channel is full. The stream's lock must be held.
+func (sub *Subscription) send(event QuoteEvent) {
+	if sub.closed {
+		return
+	}
+	for {
+		select {
+		case sub.ch <- event:
+			return
+		default:
+		}
+		select {
+		case <-sub.ch:
+			sub.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// close closes the channel once. The stream's lock must be held.
+func (sub *Subscription) close() {
+	if !sub.closed {
+		sub.closed = true
+		close(sub.ch)
+	}
+}
+
+// wants reports whether the subscription includes symbol
+func (sub *Subscription) wants(symbol string) bool {
+	return slices.Contains(sub.symbols, symbol)
+}
+
+// Run polls until ctx is done, then closes every subscription. A stream can
+// only be run once.
+func (q *QuoteStream) Run(ctx context.Context) error {
+	q.mu.Lock()
+	if q.running || q.closed {
+		q.mu.Unlock()
+		return fmt.Errorf("quote stream already started")
+	}
+	q.running = true
+	q.mu.Unlock()
+
+	defer q.stop()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		batches := q.poll(ctx)
+		timer.Reset(q.interval(batches))
+	}
+}
+
+// stop closes every subscription
+func (q *QuoteStream) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	for sub := range q.subs {
+		sub.close()
+	}
+	q.subs = nil
+}
+
+// interval returns the delay before the next poll, stretched so that the
+// given number of requests per poll stays within the request budget
+func (q *QuoteStream) interval(batches int) time.Duration {
+	interval := q.config.PollInterval
+	if q.config.RequestsPerMinute > 0 && batches > 0 {
+		budgeted := time.Duration(batches) * time.Minute / time.Duration(q.config.RequestsPerMinute)
+		interval = max(interval, budgeted)
+	}
+	return interval
+}
+
+// symbols returns the sorted union of every subscription's symbols
+func (q *QuoteStream) symbols() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var symbols []string
+	for sub := range q.subs {
+		for _, symbol := range sub.symbols {
+			if !slices.Contains(symbols, symbol) {
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+	slices.Sort(symbols)
+	return symbols
+}
+
+// poll fetches every subscribed symbol and returns the number of requests
+// made
+func (q *QuoteStream) poll(ctx context.Context) int {
+	symbols := q.symbols()
+	batches := 0
+	for batch := range slices.Chunk(symbols, q.config.MaxSymbolsPerRequest) {
+		batches++
+		resp, err := q.marketData.GetBestBidAsk(ctx, batch...)
+		if ctx.Err() != nil {
+			return batches
+		}
+		if err != nil {
+			q.fail(batch, err)
+			continue
+		}
+		q.update(resp.Results, time.Now())
+	}
+
+	q.checkStale(symbols, time.Now())
+	return batches
+}
+
+// update records quotes and delivers those that changed
+func (q *QuoteStream) update(results []models.BestBidAskResult, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, quote := range results {
+		symbol := strings.ToUpper(quote.Symbol)
+		state, ok := q.quotes[symbol]
+		if !ok {
+			state = &quoteState{}
+			q.quotes[symbol] = state
+		}
+
+		// Deliver changed quotes, and any quote that ends a stale period
+		changed := !state.quoted || state.stale || !sameQuote(state.quote, quote)
+		state.quote = quote
+		state.received = now
+		state.stale = false
+		state.quoted = true
+
+		if changed {
+			q.broadcast(QuoteEvent{Type: QuoteUpdate, Symbol: symbol, Quote: quote, Received: now})
+		}
+	}
+}
+
+// checkStale emits QuoteStale once for each symbol without a recent quote,
+// and forgets symbols that are no longer subscribed
+func (q *QuoteStream) checkStale(symbols []string, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for symbol := range q.quotes {
+		if !slices.Contains(symbols, symbol) {
+			delete(q.quotes, symbol)
+		}
+	}
+
+	for _, symbol := range symbols {
+		state, ok := q.quotes[symbol]
+		if !ok {
+			// Start the clock for symbols that have never been quoted
+			q.quotes[symbol] = &quoteState{quote: models.BestBidAskResult{Symbol: symbol}, received: now}
+			continue
+		}
+		if state.stale || now.Sub(state.received) <= q.config.StaleAfter {
+			continue
+		}
+
+		state.stale = true
+		event := QuoteEvent{Type: QuoteStale, Symbol: symbol, Quote: state.quote}
+		if state.quoted {
+			event.Received = state.received
+		}
+		q.broadcast(event)
+	}
+}
+
+// fail reports a failed poll to subscriptions interested in the batch
+func (q *QuoteStream) fail(batch []string, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for sub := range q.subs {
+		if slices.ContainsFunc(batch, sub.wants) {
+			sub.send(QuoteEvent{Type: QuoteError, Err: err})
+		}
+	}
+}
+
+// broadcast delivers an event to subscriptions for its symbol. The lock
+// must be held.
+func (q *QuoteStream) broadcast(event QuoteEvent) {
+	for sub := range q.subs {
+		if sub.wants(event.Symbol) {
+			sub.send(event)
+		}
+	}
+}
+
+// sameQuote reports whether two quotes have the same prices and spreads
+func sameQuote(a, b models.BestBidAskResult) bool {
+	return a.Price.Equal(b.Price) &&
+		a.BidInclusiveOfSellSpread.Equal(b.BidInclusiveOfSellSpread) &&
+		a.SellSpread.Equal(b.SellSpread) &&
+		a.AskInclusiveOfBuySpread.Equal(b.AskInclusiveOfBuySpread) &&
+		a.BuySpread.Equal(b.BuySpread)
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// quoteServer serves best bid/ask quotes for the symbols it has prices for,
+// and records the symbols requested
+type quoteServer struct {
+	mu       sync.Mutex
+	prices   map[string]string
+	requests [][]string
+}
+
+func (s *quoteServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	symbols := r.URL.Query()["symbol"]
+	s.requests = append(s.requests, symbols)
+
+	var resp models.BestBidAskResponse
+	for _, symbol := range symbols {
+		if price, ok := s.prices[symbol]; ok {
+			resp.Results = append(resp.Results, models.BestBidAskResult{Symbol: symbol, Price: models.MustParseDecimal(price)})
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *quoteServer) setPrice(symbol, price string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prices[symbol] = price
+}
+
+// nextEvent waits for an event on sub, failing the test after a second
+func nextEvent(t *testing.T, sub *Subscription) QuoteEvent {
+	t.Helper()
+	select {
+	case event, ok := <-sub.C:
+		if !ok {
+			t.Fatal("subscription closed")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for quote event")
+	}
+	return QuoteEvent{}
+}
+
+func TestQuoteStream_DeduplicatesUnchangedQuotes(t *testing.T) {
+	server := &quoteServer{prices: map[string]string{"BTC-USD": "45000.00"}}
+	c := newTestClient(t, server.handle)
+
+	stream := c.MarketData.NewQuoteStream(QuoteStreamConfig{PollInterval: 5 * time.Millisecond, RequestsPerMinute: -1})
+	sub := stream.Subscribe("btc-usd")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- stream.Run(ctx) }()
+
+	event := nextEvent(t, sub)
+	if event.Type != QuoteUpdate || event.Symbol != "BTC-USD" || event.Quote.Price.String() != "45000.00" {
+		t.Fatalf("first event = %+v", event)
+	}
+
+	// Several polls return the same quote before the price moves
+	time.Sleep(30 * time.Millisecond)
+	server.setPrice("BTC-USD", "45001.00")
+
+	event = nextEvent(t, sub)
+	if event.Type != QuoteUpdate || event.Quote.Price.String() != "45001.00" {
+		t.Errorf("second event = %+v, want update to 45001.00", event)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+	if _, ok := <-sub.C; ok {
+		t.Error("subscription still open after Run returned")
+	}
+}
+
+func TestQuoteStream_BatchesSymbols(t *testing.T) {
+	server := &quoteServer{prices: map[string]string{"BTC-USD": "45000", "ETH-USD": "3000", "SOL-USD": "100"}}
+	c := newTestClient(t, server.handle)
+
+	stream := c.MarketData.NewQuoteStream(QuoteStreamConfig{MaxSymbolsPerRequest: 2})
+	btc := stream.Subscribe("BTC-USD", "ETH-USD")
+	sol := stream.Subscribe("SOL-USD", "ETH-USD")
+
+	if batches := stream.poll(context.Background()); batches != 2 {
+		t.Errorf("poll() made %d requests, want 2", batches)
+	}
+	want := [][]string{{"BTC-USD", "ETH-USD"}, {"SOL-USD"}}
+	if !slices.EqualFunc(server.requests, want, slices.Equal) {
+		t.Errorf("requests = %v, want %v", server.requests, want)
+	}
+
+	if len(btc.C) != 2 || len(sol.C) != 2 {
+		t.Errorf("buffered events = %d and %d, want 2 each", len(btc.C), len(sol.C))
+	}
+
+	// A later subscriber gets the last known quote straight away
+	late := stream.Subscribe("SOL-USD")
+	if event := nextEvent(t, late); event.Quote.Price.String() != "100" {
+		t.Errorf("snapshot = %+v, want SOL-USD at 100", event)
+	}
+}
+
+func TestQuoteStream_Stale(t *testing.T) {
+	server := &quoteServer{prices: map[string]string{"BTC-USD": "45000"}}
+	c := newTestClient(t, server.handle)
+
+	stream := c.MarketData.NewQuoteStream(QuoteStreamConfig{StaleAfter: time.Minute})
+	sub := stream.Subscribe("BTC-USD")
+	ctx := context.Background()
+
+	stream.poll(ctx)
+	if event := nextEvent(t, sub); event.Type != QuoteUpdate {
+		t.Fatalf("event = %+v, want update", event)
+	}
+
+	// The symbol drops out of the response for longer than StaleAfter
+	delete(server.prices, "BTC-USD")
+	now := time.Now()
+	stream.checkStale([]string{"BTC-USD"}, now.Add(2*time.Minute))
+	stream.checkStale([]string{"BTC-USD"}, now.Add(3*time.Minute))
+
+	event := nextEvent(t, sub)
+	if event.Type != QuoteStale || event.Quote.Price.String() != "45000" {
+		t.Errorf("event = %+v, want stale with the last quote", event)
+	}
+	if len(sub.C) != 0 {
+		t.Errorf("%d more events, want stale reported once", len(sub.C))
+	}
+
+	// The same quote returning ends the stale period and is delivered again
+	server.setPrice("BTC-USD", "45000")
+	stream.poll(ctx)
+	if event := nextEvent(t, sub); event.Type != QuoteUpdate {
+		t.Errorf("event = %+v, want update", event)
+	}
+}
+
+func TestQuoteStream_Unsubscribe(t *testing.T) {
+	server := &quoteServer{prices: map[string]string{"BTC-USD": "45000", "ETH-USD": "3000"}}
+	c := newTestClient(t, server.handle)
+
+	stream := c.MarketData.NewQuoteStream(QuoteStreamConfig{})
+	btc := stream.Subscribe("BTC-USD")
+	eth := stream.Subscribe("ETH-USD")
+
+	btc.Unsubscribe()
+	if _, ok := <-btc.C; ok {
+		t.Error("channel open after Unsubscribe")
+	}
+	btc.Unsubscribe()
+
+	stream.poll(context.Background())
+	if want := [][]string{{"ETH-USD"}}; !slices.EqualFunc(server.requests, want, slices.Equal) {
+		t.Errorf("requests = %v, want %v", server.requests, want)
+	}
+	if event := nextEvent(t, eth); event.Symbol != "ETH-USD" {
+		t.Errorf("event = %+v, want ETH-USD", event)
+	}
+}
+
+func TestQuoteStream_Interval(t *testing.T) {
+	stream := (&MarketDataService{}).NewQuoteStream(QuoteStreamConfig{PollInterval: time.Second, RequestsPerMinute: 30})
+
+	if got := stream.interval(1); got != 2*time.Second {
+		t.Errorf("interval(1) = %v, want 2s", got)
+	}
+	if got := stream.interval(3); got != 6*time.Second {
+		t.Errorf("interval(3) = %v, want 6s", got)
+	}
+
+	fast := (&MarketDataService{}).NewQuoteStream(QuoteStreamConfig{PollInterval: 5 * time.Second})
+	if got := fast.interval(1); got != 5*time.Second {
+		t.Errorf("interval(1) = %v, want 5s", got)
+	}
+}
+
+func TestQuoteStream_DropsOldestWhenFull(t *testing.T) {
+	stream := (&MarketDataService{}).NewQuoteStream(QuoteStreamConfig{BufferSize: 2})
+	sub := stream.Subscribe("BTC-USD")
+
+	for _, price := range []string{"1", "2", "3"} {
+		stream.update([]models.BestBidAskResult{{Symbol: "BTC-USD", Price: models.MustParseDecimal(price)}}, time.Now())
+	}
+
+	if sub.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", sub.Dropped())
+	}
+	if event := nextEvent(t, sub); event.Quote.Price.String() != "2" {
+		t.Errorf("oldest buffered price = %s, want 2", event.Quote.Price)
+	}
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const ordersPath = "/api/v1/crypto/trading/orders/"
+
+// idempotency describes whether a request can be safely resent after an
+// attempt whose outcome is unknown
+type idempotency int
+
+const (
+	// nonIdempotent requests are never resent once they may have reached the server
+	nonIdempotent idempotency = iota
+	// idempotent requests can be resent as-is (reads and cancels)
+	idempotent
+	// keyedByClientOrderID requests are deduplicated by their client_order_id
+	// and are resent only after confirming the order does not already exist
+	keyedByClientOrderID
+)
+
+// apiRequest describes a single logical API call. The body is marshaled once
+// so that every attempt sends and signs identical bytes.
+type apiRequest struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        []byte
+	idempotency idempotency
+
+	// reconcile is consulted before resending a keyed request. It reports
+	// whether an earlier attempt already took effect, in which case the
+	// request is not sent again.
+	reconcile func(ctx context.Context) (bool, error)
+}
+
+// apiResponse is a fully read HTTP response
+type apiResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+
+	// attempts is the number of times the request was sent
+	attempts int
+
+	// timestampRejected is set when the API rejected the request's
+	// x-timestamp, see Client.observeClock
+	timestampRejected bool
+
+	// reconciled is set when the reconcile hook found that an earlier
+	// attempt succeeded, so there is no body to decode
+	reconciled bool
+}
+
+// newAPIRequest marshals the body and classifies the request
+func newAPIRequest(r *Request) (*apiRequest, error) {
+	req := &apiRequest{
+		method: r.Method,
+		path:   r.Path,
+		query:  r.Query,
+		header: r.Header,
+	}
+
+	if r.Body != nil {
+		bodyBytes, err := json.Marshal(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal body: %w", err)
+		}
+		req.body = bodyBytes
+	}
+
+	req.idempotency = classifyRequest(req.method, req.path, req.body)
+	return req, nil
+}
+
+// classifyRequest determines whether a request is safe to resend
+func classifyRequest(method, path string, body []byte) idempotency {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return idempotent
+	case http.MethodPost:
+		// Cancelling an order twice has the same effect as cancelling it once
+		if strings.HasPrefix(path, ordersPath) && strings.HasSuffix(path, "/cancel/") {
+			return idempotent
+		}
+		// Order placement is keyed by client_order_id
+		if path == ordersPath {
+			var keyed struct {
+				ClientOrderID string `json:"client_order_id"`
+			}
+			if err := json.Unmarshal(body, &keyed); err == nil && keyed.ClientOrderID != "" {
+				return keyedByClientOrderID
+			}
+		}
+	}
+	return nonIdempotent
+}
+
+// canResend reports whether the request may be sent again after an attempt
+// that may have reached the server
+func (r *apiRequest) canResend() bool {
+	switch r.idempotency {
+	case idempotent:
+		return true
+	case keyedByClientOrderID:
+		return r.reconcile != nil
+	default:
+		return false
+	}
+}
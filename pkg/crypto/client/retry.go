@@ -0,0 +1,172 @@
+package client
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides whether a failed attempt is retried and how long to
+// wait before the next one. Implementations must be safe for concurrent use.
+//
+// The client only consults the policy for attempts that failed, and never
+// resends a request that is unsafe to repeat regardless of the policy.
+type RetryPolicy interface {
+	// NextDelay returns the delay before the next attempt, and false if the
+	// request should not be retried
+	NextDelay(attempt Attempt) (time.Duration, bool)
+}
+
+// Attempt describes the outcome of a single request attempt
+type Attempt struct {
+	Method string
+	Path   string
+
+	// Number is the 1-based attempt number
+	Number int
+
+	// StatusCode and Header are set when a response was received
+	StatusCode int
+	Header     http.Header
+
+	// Err is set when no response was received
+	Err error
+
+	// Elapsed is the time since the first attempt started
+	Elapsed time.Duration
+
+	// Retry and Delay record the decision taken after this attempt. They
+	// are only meaningful to attempt observers.
+	Retry bool
+	Delay time.Duration
+}
+
+// ExponentialBackoff retries with exponentially growing, fully jittered
+// delays. A zero value field falls back to the corresponding default.
+type ExponentialBackoff struct {
+	// BaseDelay is the upper bound of the first delay
+	BaseDelay time.Duration
+	// MaxDelay caps the upper bound of any single backoff delay
+	MaxDelay time.Duration
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+	// MaxElapsed stops retrying once the next attempt would start later
+	// than this long after the first one
+	MaxElapsed time.Duration
+
+	// StatusRules overrides whether a status code is retried. Codes not
+	// listed use the defaults: 429, 500, 502, 503 and 504 are retried.
+	StatusRules map[int]bool
+
+	// IgnoreRetryAfter disables honoring the Retry-After response header
+	IgnoreRetryAfter bool
+}
+
+const (
+	defaultBaseDelay   = 500 * time.Millisecond
+	defaultMaxDelay    = 30 * time.Second
+	defaultMaxAttempts = 4
+	defaultMaxElapsed  = 2 * time.Minute
+)
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() *ExponentialBackoff {
+	return &ExponentialBackoff{
+		BaseDelay:   defaultBaseDelay,
+		MaxDelay:    defaultMaxDelay,
+		MaxAttempts: defaultMaxAttempts,
+		MaxElapsed:  defaultMaxElapsed,
+	}
+}
+
+// NextDelay implements RetryPolicy
+func (p *ExponentialBackoff) NextDelay(attempt Attempt) (time.Duration, bool) {
+	if attempt.Err == nil && !p.retryStatus(attempt.StatusCode) {
+		return 0, false
+	}
+
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	if attempt.Number >= maxAttempts {
+		return 0, false
+	}
+
+	delay := p.backoff(attempt.Number)
+	if !p.IgnoreRetryAfter {
+		if retryAfter, ok := parseRetryAfter(attempt.Header, time.Now()); ok && retryAfter > delay {
+			delay = retryAfter
+		}
+	}
+
+	maxElapsed := p.MaxElapsed
+	if maxElapsed <= 0 {
+		maxElapsed = defaultMaxElapsed
+	}
+	if attempt.Elapsed+delay > maxElapsed {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+// retryStatus reports whether a response status code should be retried
+func (p *ExponentialBackoff) retryStatus(code int) bool {
+	if retry, ok := p.StatusRules[code]; ok {
+		return retry
+	}
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns a random delay in [0, min(MaxDelay, BaseDelay*2^(n-1)))
+func (p *ExponentialBackoff) backoff(n int) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = defaultBaseDelay
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxDelay
+	}
+
+	ceiling := maxDelay
+	if shift := n - 1; shift < 32 && base<<shift > 0 && base<<shift < maxDelay {
+		ceiling = base << shift
+	}
+	return rand.N(ceiling)
+}
+
+// parseRetryAfter reads a Retry-After header given either as a number of
+// seconds or as an HTTP date
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoff_NextDelay(t *testing.T) {
+	policy := &ExponentialBackoff{
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    time.Second,
+		MaxAttempts: 3,
+		MaxElapsed:  10 * time.Second,
+		StatusRules: map[int]bool{http.StatusServiceUnavailable: false, http.StatusRequestTimeout: true},
+	}
+
+	tests := []struct {
+		name      string
+		attempt   Attempt
+		wantRetry bool
+		maxDelay  time.Duration
+	}{
+		{"retries 429", Attempt{Number: 1, StatusCode: 429}, true, 100 * time.Millisecond},
+		{"backoff grows", Attempt{Number: 2, StatusCode: 500}, true, 200 * time.Millisecond},
+		{"transport error", Attempt{Number: 1, Err: context.DeadlineExceeded}, true, 100 * time.Millisecond},
+		{"client error", Attempt{Number: 1, StatusCode: 400}, false, 0},
+		{"status rule disables", Attempt{Number: 1, StatusCode: 503}, false, 0},
+		{"status rule enables", Attempt{Number: 1, StatusCode: 408}, true, 100 * time.Millisecond},
+		{"max attempts", Attempt{Number: 3, StatusCode: 500}, false, 0},
+		{"max elapsed", Attempt{Number: 1, StatusCode: 500, Elapsed: 10 * time.Second}, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, retry := policy.NextDelay(tt.attempt)
+			if retry != tt.wantRetry {
+				t.Fatalf("NextDelay() retry = %v, want %v", retry, tt.wantRetry)
+			}
+			if delay < 0 || delay > tt.maxDelay {
+				t.Errorf("NextDelay() delay = %v, want in [0, %v]", delay, tt.maxDelay)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff_RetryAfter(t *testing.T) {
+	policy := &ExponentialBackoff{BaseDelay: time.Millisecond, MaxElapsed: time.Minute}
+
+	header := http.Header{}
+	header.Set("Retry-After", "7")
+	delay, retry := policy.NextDelay(Attempt{Number: 1, StatusCode: 429, Header: header})
+	if !retry || delay != 7*time.Second {
+		t.Errorf("NextDelay() = %v, %v, want 7s, true", delay, retry)
+	}
+
+	// A Retry-After beyond the elapsed budget stops retrying
+	header.Set("Retry-After", "120")
+	if _, retry := policy.NextDelay(Attempt{Number: 1, StatusCode: 429, Header: header}); retry {
+		t.Error("NextDelay() retry = true, want false when Retry-After exceeds MaxElapsed")
+	}
+
+	policy.IgnoreRetryAfter = true
+	if delay, _ := policy.NextDelay(Attempt{Number: 1, StatusCode: 429, Header: header}); delay > time.Millisecond {
+		t.Errorf("NextDelay() delay = %v, want Retry-After ignored", delay)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		got, ok := parseRetryAfter(header, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRequest_AttemptObserver(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	var attempts []Attempt
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		if n < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}, WithAttemptObserver(func(a Attempt) {
+		attempts = append(attempts, a)
+	}))
+
+	if err := c.do(context.Background(), "GET", "/api/v1/crypto/trading/accounts/", nil, nil, nil); err != nil {
+		t.Fatalf("do() error = %v", err)
+	}
+
+	if len(attempts) != 3 {
+		t.Fatalf("observed %d attempts, want 3", len(attempts))
+	}
+	for i, a := range attempts {
+		if a.Number != i+1 {
+			t.Errorf("attempts[%d].Number = %d, want %d", i, a.Number, i+1)
+		}
+		if wantRetry := i < 2; a.Retry != wantRetry {
+			t.Errorf("attempts[%d].Retry = %v, want %v", i, a.Retry, wantRetry)
+		}
+	}
+}
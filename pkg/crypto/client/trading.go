@@ -5,11 +5,19 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
 	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
 )
 
+// reconcileWindow is how far before an order is placed to search when
+// checking whether an earlier attempt already created it. The search starts
+// from the server's time as estimated by the signer, and the window allows
+// for error in that estimate.
+const reconcileWindow = 5 * time.Minute
+
 // GetTradingPairs fetches the list of available trading pairs
 func (s *TradingService) GetTradingPairs(ctx context.Context, symbols ...string) (*models.TradingPairsResponse, error) {
 	query := url.Values{}
@@ -18,7 +26,7 @@ func (s *TradingService) GetTradingPairs(ctx context.Context, symbols ...string)
 	}
 
 	var result models.TradingPairsResponse
-	err := s.client.do(ctx, "GET", "/api/v1/crypto/trading/trading_pairs/", query, nil, &result)
+	err := s.client.do(ctx, "GET", tradingPairsPath, query, nil, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -58,16 +66,16 @@ func (s *TradingService) GetOrders(ctx context.Context, filter *models.OrdersFil
 	
 	if filter != nil {
 		if filter.CreatedAtStart != nil {
-			query.Set("created_at_start", filter.CreatedAtStart.Format("2006-01-02T15:04:05Z"))
+			query.Set("created_at_start", models.FormatTime(*filter.CreatedAtStart))
 		}
 		if filter.CreatedAtEnd != nil {
-			query.Set("created_at_end", filter.CreatedAtEnd.Format("2006-01-02T15:04:05Z"))
+			query.Set("created_at_end", models.FormatTime(*filter.CreatedAtEnd))
 		}
 		if filter.UpdatedAtStart != nil {
-			query.Set("updated_at_start", filter.UpdatedAtStart.Format("2006-01-02T15:04:05Z"))
+			query.Set("updated_at_start", models.FormatTime(*filter.UpdatedAtStart))
 		}
 		if filter.UpdatedAtEnd != nil {
-			query.Set("updated_at_end", filter.UpdatedAtEnd.Format("2006-01-02T15:04:05Z"))
+			query.Set("updated_at_end", models.FormatTime(*filter.UpdatedAtEnd))
 		}
 		if filter.Symbol != "" {
 			query.Set("symbol", strings.ToUpper(filter.Symbol))
@@ -76,13 +84,13 @@ func (s *TradingService) GetOrders(ctx context.Context, filter *models.OrdersFil
 			query.Set("id", filter.ID)
 		}
 		if filter.Side != "" {
-			query.Set("side", filter.Side)
+			query.Set("side", filter.Side.String())
 		}
 		if filter.State != "" {
-			query.Set("state", filter.State)
+			query.Set("state", filter.State.String())
 		}
 		if filter.Type != "" {
-			query.Set("type", filter.Type)
+			query.Set("type", filter.Type.String())
 		}
 		if filter.Cursor != "" {
 			query.Set("cursor", filter.Cursor)
@@ -95,26 +103,41 @@ func (s *TradingService) GetOrders(ctx context.Context, filter *models.OrdersFil
 	var result models.OrdersResponse
 	err := s.client.do(ctx, "GET", "/api/v1/crypto/trading/orders/", query, nil, &result)
 	if err != nil {
-		return nil, err
+		return nil, errors.MapValidationError(err, filter)
 	}
 	return &result, nil
 }
 
 // PlaceOrder places a new crypto order
 func (s *TradingService) PlaceOrder(ctx context.Context, req *models.PlaceOrderRequest) (*models.Order, error) {
+	// Work on a copy so the caller's request is never modified
+	req = req.Clone()
+
 	// Generate client order ID if not provided
 	if req.ClientOrderID == "" {
 		req.ClientOrderID = uuid.New().String()
 	}
 
 	// Validate request
-	if err := s.validateOrderRequest(req); err != nil {
+	if err := req.Validate(); err != nil {
 		return nil, err
 	}
+	setDefaultTimeInForce(req)
 
 	// Ensure symbol is uppercase
 	req.Symbol = strings.ToUpper(req.Symbol)
 
+	// Round to the pair's increments and check size limits before sending
+	if s.client.quantize {
+		pair, err := s.client.Instruments.Get(ctx, req.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		if req, err = quantizeOrder(req, pair, s.client.roundingMode); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create the request body with the correct order config field name
 	body := map[string]interface{}{
 		"symbol":          req.Symbol,
@@ -125,113 +148,100 @@ func (s *TradingService) PlaceOrder(ctx context.Context, req *models.PlaceOrderR
 
 	// Add the appropriate order config based on type
 	switch req.Type {
-	case "market":
+	case models.OrderTypeMarket:
 		if req.MarketOrderConfig != nil {
 			body["market_order_config"] = req.MarketOrderConfig
 		}
-	case "limit":
+	case models.OrderTypeLimit:
 		if req.LimitOrderConfig != nil {
 			body["limit_order_config"] = req.LimitOrderConfig
 		}
-	case "stop_loss":
+	case models.OrderTypeStopLoss:
 		if req.StopLossOrderConfig != nil {
 			body["stop_loss_order_config"] = req.StopLossOrderConfig
 		}
-	case "stop_limit":
+	case models.OrderTypeStopLimit:
 		if req.StopLimitOrderConfig != nil {
 			body["stop_limit_order_config"] = req.StopLimitOrderConfig
 		}
 	}
 
+	// If an attempt fails in a way that leaves its outcome unknown, look the
+	// order up by client_order_id before resending so it is never placed twice
 	var result models.Order
-	err := s.client.do(ctx, "POST", "/api/v1/crypto/trading/orders/", nil, body, &result)
-	if err != nil {
-		return nil, err
+	since := s.client.auth.Now().Add(-reconcileWindow)
+	reconcile := func(ctx context.Context) (bool, error) {
+		existing, err := s.findOrderByClientOrderID(ctx, req.Symbol, req.ClientOrderID, since)
+		if err != nil {
+			return false, err
+		}
+		if existing == nil {
+			return false, nil
+		}
+		result = *existing
+		return true, nil
+	}
+
+	apiReq := &Request{
+		Method: "POST",
+		Path:   ordersPath,
+		Body:   body,
+		Result: &result,
+	}
+	if err := s.client.doRequest(ctx, apiReq, reconcile); err != nil {
+		return nil, errors.MapValidationError(err, req)
 	}
 	return &result, nil
 }
 
+// findOrderByClientOrderID searches orders created since the given time for
+// one with a matching client order ID. It returns nil if none exists.
+func (s *TradingService) findOrderByClientOrderID(ctx context.Context, symbol, clientOrderID string, since time.Time) (*models.Order, error) {
+	filter := &models.OrdersFilter{
+		Symbol:         symbol,
+		CreatedAtStart: &since,
+	}
+
+	for {
+		resp, err := s.GetOrders(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range resp.Results {
+			if resp.Results[i].ClientOrderID == clientOrderID {
+				return &resp.Results[i], nil
+			}
+		}
+
+		filter.Cursor = extractCursor(resp.Next)
+		if filter.Cursor == "" {
+			return nil, nil
+		}
+	}
+}
+
 // CancelOrder cancels an open crypto order
 func (s *TradingService) CancelOrder(ctx context.Context, orderID string) error {
 	path := fmt.Sprintf("/api/v1/crypto/trading/orders/%s/cancel/", orderID)
 	return s.client.do(ctx, "POST", path, nil, nil, nil)
 }
 
-// validateOrderRequest validates the order request parameters
-func (s *TradingService) validateOrderRequest(req *models.PlaceOrderRequest) error {
-	if req.Symbol == "" {
-		return fmt.Errorf("symbol is required")
-	}
-	if req.Side != "buy" && req.Side != "sell" {
-		return fmt.Errorf("invalid side: must be 'buy' or 'sell'")
-	}
-	
-	// Validate order type and corresponding config
+// setDefaultTimeInForce makes orders that take a time in force good till
+// cancelled unless one was given
+func setDefaultTimeInForce(req *models.PlaceOrderRequest) {
+	var tif *models.TimeInForce
 	switch req.Type {
-	case "market":
-		if req.MarketOrderConfig == nil {
-			return fmt.Errorf("market_order_config is required for market orders")
-		}
-		if req.MarketOrderConfig.AssetQuantity == 0 && req.MarketOrderConfig.QuoteAmount == 0 {
-			return fmt.Errorf("either asset_quantity or quote_amount must be specified")
-		}
-		if req.MarketOrderConfig.AssetQuantity != 0 && req.MarketOrderConfig.QuoteAmount != 0 {
-			return fmt.Errorf("only one of asset_quantity or quote_amount can be specified")
-		}
-	case "limit":
-		if req.LimitOrderConfig == nil {
-			return fmt.Errorf("limit_order_config is required for limit orders")
-		}
-		if req.LimitOrderConfig.LimitPrice <= 0 {
-			return fmt.Errorf("limit_price must be greater than 0")
-		}
-		if req.LimitOrderConfig.AssetQuantity == 0 && req.LimitOrderConfig.QuoteAmount == 0 {
-			return fmt.Errorf("either asset_quantity or quote_amount must be specified")
-		}
-		if req.LimitOrderConfig.AssetQuantity != 0 && req.LimitOrderConfig.QuoteAmount != 0 {
-			return fmt.Errorf("only one of asset_quantity or quote_amount can be specified")
-		}
-		if req.LimitOrderConfig.TimeInForce == "" {
-			req.LimitOrderConfig.TimeInForce = "gtc"
-		}
-	case "stop_loss":
-		if req.StopLossOrderConfig == nil {
-			return fmt.Errorf("stop_loss_order_config is required for stop loss orders")
-		}
-		if req.StopLossOrderConfig.StopPrice <= 0 {
-			return fmt.Errorf("stop_price must be greater than 0")
-		}
-		if req.StopLossOrderConfig.AssetQuantity == 0 && req.StopLossOrderConfig.QuoteAmount == 0 {
-			return fmt.Errorf("either asset_quantity or quote_amount must be specified")
-		}
-		if req.StopLossOrderConfig.AssetQuantity != 0 && req.StopLossOrderConfig.QuoteAmount != 0 {
-			return fmt.Errorf("only one of asset_quantity or quote_amount can be specified")
-		}
-		if req.StopLossOrderConfig.TimeInForce == "" {
-			req.StopLossOrderConfig.TimeInForce = "gtc"
-		}
-	case "stop_limit":
-		if req.StopLimitOrderConfig == nil {
-			return fmt.Errorf("stop_limit_order_config is required for stop limit orders")
-		}
-		if req.StopLimitOrderConfig.StopPrice <= 0 {
-			return fmt.Errorf("stop_price must be greater than 0")
-		}
-		if req.StopLimitOrderConfig.LimitPrice <= 0 {
-			return fmt.Errorf("limit_price must be greater than 0")
-		}
-		if req.StopLimitOrderConfig.AssetQuantity == 0 && req.StopLimitOrderConfig.QuoteAmount == 0 {
-			return fmt.Errorf("either asset_quantity or quote_amount must be specified")
-		}
-		if req.StopLimitOrderConfig.AssetQuantity != 0 && req.StopLimitOrderConfig.QuoteAmount != 0 {
-			return fmt.Errorf("only one of asset_quantity or quote_amount can be specified")
-		}
-		if req.StopLimitOrderConfig.TimeInForce == "" {
-			req.StopLimitOrderConfig.TimeInForce = "gtc"
-		}
+	case models.OrderTypeLimit:
+		tif = &req.LimitOrderConfig.TimeInForce
+	case models.OrderTypeStopLoss:
+		tif = &req.StopLossOrderConfig.TimeInForce
+	case models.OrderTypeStopLimit:
+		tif = &req.StopLimitOrderConfig.TimeInForce
 	default:
-		return fmt.Errorf("invalid order type: must be 'market', 'limit', 'stop_loss', or 'stop_limit'")
+		return
 	}
-
-	return nil
-}
\ No newline at end of file
+	if *tif == "" {
+		*tif = models.TimeInForceGTC
+	}
+}
@@ -3,42 +3,92 @@ package client
 import (
 	"context"
 	"fmt"
+	"strings"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
 )
 
-// GetAllTradeablePairs fetches all tradeable cryptocurrency pairs
-func (c *Client) GetAllTradeablePairs(ctx context.Context) ([]TradingPairInfo, error) {
-	// Get all trading pairs (no symbols = all pairs)
-	response, err := c.Trading.GetTradingPairs(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get trading pairs: %w", err)
+// tradableStatus is the status of pairs that accept orders
+const tradableStatus = "tradable"
+
+// PairFilter restricts the pairs returned by GetAllTradeablePairs and
+// GetAllTradeableSymbols
+type PairFilter func(*pairFilter)
+
+type pairFilter struct {
+	quoteCodes []string
+	statuses   []string
+}
+
+// FilterQuoteCode keeps pairs quoted in one of the given currencies, such as
+// "USD"
+func FilterQuoteCode(codes ...string) PairFilter {
+	return func(f *pairFilter) {
+		f.quoteCodes = append(f.quoteCodes, codes...)
+	}
+}
+
+// FilterStatus keeps pairs with one of the given statuses instead of only
+// tradable pairs
+func FilterStatus(statuses ...string) PairFilter {
+	return func(f *pairFilter) {
+		f.statuses = append(f.statuses, statuses...)
+	}
+}
+
+// matches reports whether pair passes the filter
+func (f *pairFilter) matches(pair models.TradingPair) bool {
+	return matchesAny(pair.QuoteCode, f.quoteCodes) && matchesAny(pair.Status, f.statuses)
+}
+
+// matchesAny reports whether value is one of values, ignoring case. Every
+// value matches an empty list.
+func matchesAny(value string, values []string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAllTradeablePairs fetches all tradeable cryptocurrency pairs, following
+// every page. Filters can restrict the quote currency, or select statuses
+// other than tradable.
+func (c *Client) GetAllTradeablePairs(ctx context.Context, filters ...PairFilter) ([]TradingPairInfo, error) {
+	filter := &pairFilter{}
+	for _, apply := range filters {
+		apply(filter)
+	}
+	if len(filter.statuses) == 0 {
+		filter.statuses = []string{tradableStatus}
 	}
 
+	var all []models.TradingPair
 	var pairs []TradingPairInfo
-	for _, pair := range response.Results {
-		// Only include tradable pairs
-		if pair.Status == "tradable" {
-			pairs = append(pairs, TradingPairInfo{
-				Symbol:         pair.Symbol,
-				AssetCode:      pair.AssetCode,
-				QuoteCode:      pair.QuoteCode,
-				Status:         pair.Status,
-				MinOrderSize:   pair.MinOrderSize,
-				MaxOrderSize:   pair.MaxOrderSize,
-				AssetIncrement: pair.AssetIncrement,
-				QuoteIncrement: pair.QuoteIncrement,
-			})
+	for pair, err := range c.Trading.AllTradingPairs(ctx) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trading pairs: %w", err)
+		}
+		all = append(all, pair)
+
+		if filter.matches(pair) {
+			pairs = append(pairs, newTradingPairInfo(pair))
 		}
 	}
 
-	// TODO: Handle pagination if response.Next is not empty
-	// This would require updating GetTradingPairs to accept pagination parameters
+	// Every pair has just been fetched, so refresh the order quantization cache
+	c.Instruments.store(all)
 
 	return pairs, nil
 }
 
 // GetAllTradeableSymbols returns just the symbols of all tradeable pairs
-func (c *Client) GetAllTradeableSymbols(ctx context.Context) ([]string, error) {
-	pairs, err := c.GetAllTradeablePairs(ctx)
+func (c *Client) GetAllTradeableSymbols(ctx context.Context, filters ...PairFilter) ([]string, error) {
+	pairs, err := c.GetAllTradeablePairs(ctx, filters...)
 	if err != nil {
 		return nil, err
 	}
@@ -57,8 +107,37 @@ type TradingPairInfo struct {
 	AssetCode      string
 	QuoteCode      string
 	Status         string
-	MinOrderSize   string
-	MaxOrderSize   string
-	AssetIncrement string
-	QuoteIncrement string
-}
\ No newline at end of file
+	MinOrderSize   models.Decimal
+	MaxOrderSize   models.Decimal
+	AssetIncrement models.Decimal
+	QuoteIncrement models.Decimal
+}
+
+func newTradingPairInfo(pair models.TradingPair) TradingPairInfo {
+	return TradingPairInfo{
+		Symbol:         pair.Symbol,
+		AssetCode:      pair.AssetCode,
+		QuoteCode:      pair.QuoteCode,
+		Status:         pair.Status,
+		MinOrderSize:   pair.MinOrderSize,
+		MaxOrderSize:   pair.MaxOrderSize,
+		AssetIncrement: pair.AssetIncrement,
+		QuoteIncrement: pair.QuoteIncrement,
+	}
+}
+
+// Tradable reports whether the pair currently accepts orders
+func (p TradingPairInfo) Tradable() bool {
+	return p.Status == tradableStatus
+}
+
+// AssetPrecision returns the number of decimal places in asset quantities
+func (p TradingPairInfo) AssetPrecision() int32 {
+	return p.AssetIncrement.Scale()
+}
+
+// QuotePrecision returns the number of decimal places in prices and quote
+// amounts
+func (p TradingPairInfo) QuotePrecision() int32 {
+	return p.QuoteIncrement.Scale()
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// pagedPairs serves one trading pair per page
+func pagedPairs(pairs ...models.TradingPair) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		i := 0
+		fmt.Sscan(r.URL.Query().Get("cursor"), &i)
+
+		resp := models.TradingPairsResponse{Results: pairs[i : i+1]}
+		if i+1 < len(pairs) {
+			resp.Next = fmt.Sprintf("https://example.com%s?cursor=%d", tradingPairsPath, i+1)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestGetAllTradeablePairs(t *testing.T) {
+	c := newTestClient(t, pagedPairs(
+		models.TradingPair{Symbol: "BTC-USD", QuoteCode: "USD", Status: "tradable", AssetIncrement: models.MustParseDecimal("0.00000001")},
+		models.TradingPair{Symbol: "ETH-USD", QuoteCode: "USD", Status: "untradable"},
+		models.TradingPair{Symbol: "ETH-BTC", QuoteCode: "BTC", Status: "tradable"},
+		models.TradingPair{Symbol: "DOGE-USD", QuoteCode: "USD", Status: "tradable"},
+	))
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		filters []PairFilter
+		want    []string
+	}{
+		{"all pages", nil, []string{"BTC-USD", "ETH-BTC", "DOGE-USD"}},
+		{"quote code", []PairFilter{FilterQuoteCode("usd")}, []string{"BTC-USD", "DOGE-USD"}},
+		{"status", []PairFilter{FilterStatus("untradable")}, []string{"ETH-USD"}},
+		{"combined", []PairFilter{FilterQuoteCode("USD"), FilterStatus("tradable", "untradable")}, []string{"BTC-USD", "ETH-USD", "DOGE-USD"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symbols, err := c.GetAllTradeableSymbols(ctx, tt.filters...)
+			if err != nil {
+				t.Fatalf("GetAllTradeableSymbols() error = %v", err)
+			}
+			if !reflect.DeepEqual(symbols, tt.want) {
+				t.Errorf("symbols = %v, want %v", symbols, tt.want)
+			}
+		})
+	}
+
+	pairs, err := c.GetAllTradeablePairs(ctx)
+	if err != nil {
+		t.Fatalf("GetAllTradeablePairs() error = %v", err)
+	}
+	if got := pairs[0].AssetPrecision(); got != 8 {
+		t.Errorf("AssetPrecision() = %d, want 8", got)
+	}
+}
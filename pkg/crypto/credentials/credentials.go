@@ -0,0 +1,276 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/auth"
+)
+
+// Environment variables consulted by FromEnv and Load
+const (
+	EnvAPIKey          = "ROBINHOOD_API_KEY"
+	EnvPrivateKey      = "ROBINHOOD_PRIVATE_KEY"
+	EnvPrivateKeyFile  = "ROBINHOOD_PRIVATE_KEY_FILE"
+	EnvProfile         = "ROBINHOOD_PROFILE"
+	EnvCredentialsFile = "ROBINHOOD_CREDENTIALS_FILE"
+)
+
+// DefaultProfile is the profile used when none is specified
+const DefaultProfile = "default"
+
+// ErrNoCredentials is returned when no credentials could be found
+var ErrNoCredentials = errors.New("no Robinhood credentials found")
+
+// Credentials holds an API key and its Ed25519 signing key
+type Credentials struct {
+	APIKey     string
+	PrivateKey ed25519.PrivateKey
+}
+
+// Signer returns an auth.Signer for the private key
+func (c *Credentials) Signer() auth.Signer {
+	return auth.NewMemorySignerFromKey(c.PrivateKey)
+}
+
+// Load resolves credentials from the environment, falling back to the named
+// profile in the credentials file. An empty profile uses ROBINHOOD_PROFILE
+// or "default".
+func Load(profile string) (*Credentials, error) {
+	creds, err := FromEnv()
+	if err == nil || !errors.Is(err, ErrNoCredentials) {
+		return creds, err
+	}
+
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	path, err := DefaultFile()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: set %s and %s or create %s", ErrNoCredentials, EnvAPIKey, EnvPrivateKey, path)
+	}
+
+	return LoadFile(path, profile)
+}
+
+// FromEnv reads credentials from ROBINHOOD_API_KEY and either
+// ROBINHOOD_PRIVATE_KEY or ROBINHOOD_PRIVATE_KEY_FILE
+func FromEnv() (*Credentials, error) {
+	apiKey := os.Getenv(EnvAPIKey)
+	privateKey := os.Getenv(EnvPrivateKey)
+	privateKeyFile := os.Getenv(EnvPrivateKeyFile)
+
+	if apiKey == "" && privateKey == "" && privateKeyFile == "" {
+		return nil, ErrNoCredentials
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("%s is not set", EnvAPIKey)
+	}
+
+	var key ed25519.PrivateKey
+	var err error
+	switch {
+	case privateKey != "":
+		key, err = ParsePrivateKey([]byte(privateKey))
+	case privateKeyFile != "":
+		key, err = LoadPrivateKeyFile(privateKeyFile)
+	default:
+		return nil, fmt.Errorf("%s or %s must be set", EnvPrivateKey, EnvPrivateKeyFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credentials{APIKey: apiKey, PrivateKey: key}, nil
+}
+
+// DefaultFile returns the credentials file path, which is
+// ROBINHOOD_CREDENTIALS_FILE if set and ~/.robinhood/credentials otherwise
+func DefaultFile() (string, error) {
+	if path := os.Getenv(EnvCredentialsFile); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".robinhood", "credentials"), nil
+}
+
+// LoadFile reads a profile from an INI-style credentials file:
+//
+//	[default]
+//	api_key = rh-api-...
+//	private_key = <base64 key or seed>
+//
+//	[bot]
+//	api_key = rh-api-...
+//	private_key_file = ~/.robinhood/bot.pem
+//
+// Relative private_key_file paths are resolved against the directory of the
+// credentials file.
+func LoadFile(path, profile string) (*Credentials, error) {
+	data, err := readPrivateFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := parseProfiles(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	values, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in %s", profile, path)
+	}
+
+	apiKey := values["api_key"]
+	if apiKey == "" {
+		return nil, fmt.Errorf("profile %q in %s has no api_key", profile, path)
+	}
+
+	var key ed25519.PrivateKey
+	switch {
+	case values["private_key"] != "":
+		key, err = ParsePrivateKey([]byte(values["private_key"]))
+	case values["private_key_file"] != "":
+		keyPath := expandHome(values["private_key_file"])
+		if !filepath.IsAbs(keyPath) {
+			keyPath = filepath.Join(filepath.Dir(path), keyPath)
+		}
+		key, err = LoadPrivateKeyFile(keyPath)
+	default:
+		return nil, fmt.Errorf("profile %q in %s has no private_key or private_key_file", profile, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", profile, err)
+	}
+
+	return &Credentials{APIKey: apiKey, PrivateKey: key}, nil
+}
+
+// LoadPrivateKeyFile reads a private key file in any format accepted by
+// ParsePrivateKey. Files readable by other users are rejected.
+func LoadPrivateKeyFile(path string) (ed25519.PrivateKey, error) {
+	data, err := readPrivateFile(expandHome(path))
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// ParsePrivateKey parses an Ed25519 private key given as a PEM encoded
+// PKCS#8 key, a base64 encoded 64-byte private key, or a base64 encoded
+// 32-byte seed as generated by the Python examples
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	data = bytes.TrimSpace(data)
+
+	if block, _ := pem.Decode(data); block != nil {
+		if block.Type != "PRIVATE KEY" {
+			return nil, fmt.Errorf("unsupported PEM block type %q: expected PKCS#8 \"PRIVATE KEY\"", block.Type)
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PKCS#8 private key: %w", err)
+		}
+		key, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("PKCS#8 key is %T, not Ed25519", parsed)
+		}
+		return key, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode private key: %w", err)
+	}
+
+	switch len(raw) {
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	default:
+		return nil, fmt.Errorf("invalid private key size: expected %d or %d, got %d", ed25519.PrivateKeySize, ed25519.SeedSize, len(raw))
+	}
+}
+
+// readPrivateFile reads a file holding secrets, refusing it if other users
+// can read it
+func readPrivateFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o004 != 0 {
+		return nil, fmt.Errorf("%s is world-readable (mode %04o); restrict it with chmod 600", path, info.Mode().Perm())
+	}
+	return os.ReadFile(path)
+}
+
+// parseProfiles parses an INI-style file into profile sections
+func parseProfiles(data []byte) (map[string]map[string]string, error) {
+	profiles := make(map[string]map[string]string)
+	var current map[string]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if profiles[name] == nil {
+				profiles[name] = make(map[string]string)
+			}
+			current = profiles[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNum)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: key outside of a [profile] section", lineNum)
+		}
+		current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return profiles, scanner.Err()
+}
+
+// expandHome replaces a leading ~/ with the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
@@ -2,7 +2,25 @@ package errors
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// Sentinel errors for classifying failures with errors.Is
+var (
+	ErrUnauthorized     = errors.New("unauthorized")
+	ErrForbidden        = errors.New("forbidden")
+	ErrNotFound         = errors.New("not found")
+	ErrRateLimited      = errors.New("rate limited")
+	ErrValidation       = errors.New("validation error")
+	ErrServer           = errors.New("server error")
+	ErrTimestampExpired = errors.New("request timestamp expired")
 )
 
 type ErrorDetail struct {
@@ -10,10 +28,23 @@ type ErrorDetail struct {
 	Detail string `json:"detail"`
 }
 
+// RequestInfo describes the request that produced an error response
+type RequestInfo struct {
+	Method string
+	Path   string
+
+	// Attempts is the number of times the request was sent
+	Attempts int
+
+	// Header holds the response headers of the final attempt
+	Header http.Header
+}
+
 type APIError struct {
-	Type       string        `json:"type"`
-	Errors     []ErrorDetail `json:"errors"`
-	StatusCode int           `json:"-"`
+	Type        string        `json:"type"`
+	Errors      []ErrorDetail `json:"errors"`
+	StatusCode  int           `json:"-"`
+	RequestInfo `json:"-"`
 }
 
 func (e *APIError) Error() string {
@@ -36,11 +67,191 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API error (status %d): %s - %s", e.StatusCode, e.Type, details)
 }
 
+// Is reports whether the error matches one of the sentinel errors
+func (e *APIError) Is(target error) bool {
+	if target == ErrValidation && e.Type == "validation_error" {
+		return true
+	}
+	return statusIs(e.StatusCode, target)
+}
+
+// Retryable reports whether the same request may succeed if sent again
+func (e *APIError) Retryable() bool {
+	return statusRetryable(e.StatusCode)
+}
+
+// Temporary reports whether the error is caused by a transient condition
+// such as rate limiting or service unavailability
+func (e *APIError) Temporary() bool {
+	return statusTemporary(e.StatusCode)
+}
+
+// StatusError is an error response whose body is not a structured API error,
+// such as an HTML page from a proxy
+type StatusError struct {
+	StatusCode int
+	Body       string
+	RequestInfo
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Body)
+}
+
+// Is reports whether the error matches one of the sentinel errors
+func (e *StatusError) Is(target error) bool {
+	return statusIs(e.StatusCode, target)
+}
+
+// Retryable reports whether the same request may succeed if sent again
+func (e *StatusError) Retryable() bool {
+	return statusRetryable(e.StatusCode)
+}
+
+// Temporary reports whether the error is caused by a transient condition
+func (e *StatusError) Temporary() bool {
+	return statusTemporary(e.StatusCode)
+}
+
 func ParseAPIError(body []byte, statusCode int) error {
+	return ParseResponseError(body, statusCode, RequestInfo{})
+}
+
+// ParseResponseError parses an error response body and attaches details of
+// the request that produced it. It returns an *APIError for structured
+// bodies and a *StatusError otherwise.
+func ParseResponseError(body []byte, statusCode int, info RequestInfo) error {
 	var apiErr APIError
 	if err := json.Unmarshal(body, &apiErr); err != nil {
-		return fmt.Errorf("status %d: %s", statusCode, string(body))
+		return &StatusError{StatusCode: statusCode, Body: string(body), RequestInfo: info}
 	}
 	apiErr.StatusCode = statusCode
+	apiErr.RequestInfo = info
 	return &apiErr
-}
\ No newline at end of file
+}
+
+// RequestError is returned when a request failed without receiving a
+// response, for example because of a network error
+type RequestError struct {
+	RequestInfo
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%s %s: max retries exceeded after %d attempts: %v", e.Method, e.Path, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("%s %s: %v", e.Method, e.Path, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the failure happened in transport, in which
+// case sending the request again may succeed
+func (e *RequestError) Retryable() bool {
+	var urlErr *url.Error
+	return errors.As(e.Err, &urlErr)
+}
+
+// Temporary reports whether the error is caused by a transient condition
+func (e *RequestError) Temporary() bool {
+	return e.Retryable()
+}
+
+// statusIs matches a status code against the sentinel errors
+func statusIs(statusCode int, target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return statusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return statusCode == http.StatusForbidden
+	case ErrNotFound:
+		return statusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return statusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return statusCode == http.StatusBadRequest
+	case ErrServer:
+		return statusCode >= 500
+	}
+	return false
+}
+
+// statusRetryable reports whether a request failing with the status code
+// may succeed if sent again unchanged
+func statusRetryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// statusTemporary reports whether the status code signals a transient
+// condition on the server side
+func statusTemporary(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// ClockSkewError is returned when the API rejects a request as unauthorized
+// and the rejection looks caused by an expired x-timestamp, typically
+// because the local clock has drifted from the server's
+type ClockSkewError struct {
+	// Skew is the measured server time minus local time
+	Skew time.Duration
+	Err  error
+}
+
+func (e *ClockSkewError) Error() string {
+	return fmt.Sprintf("request timestamp rejected (measured clock skew %v): %v", e.Skew, e.Err)
+}
+
+func (e *ClockSkewError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches ErrTimestampExpired
+func (e *ClockSkewError) Is(target error) bool {
+	return target == ErrTimestampExpired
+}
+
+// Retryable reports true, since a request re-signed with a corrected
+// timestamp may succeed
+func (e *ClockSkewError) Retryable() bool {
+	return true
+}
+
+// Temporary reports false, since the local clock has to be corrected
+func (e *ClockSkewError) Temporary() bool {
+	return false
+}
+
+// OrderStateError is returned when an order being waited on reaches a
+// terminal state other than the ones waited for, such as an order canceled
+// before it filled
+type OrderStateError struct {
+	ID    string
+	State models.OrderState
+	Want  []models.OrderState
+}
+
+func (e *OrderStateError) Error() string {
+	want := make([]string, len(e.Want))
+	for i, state := range e.Want {
+		want[i] = state.String()
+	}
+	return fmt.Sprintf("order %s is %s, not %s", e.ID, e.State, strings.Join(want, " or "))
+}
@@ -2,7 +2,11 @@ package errors
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
 )
 
 func TestAPIError_Error(t *testing.T) {
@@ -222,4 +226,158 @@ func TestAPIError_JSONMarshaling(t *testing.T) {
 			t.Errorf("Errors[%d].Detail = %q, want %q", i, detail.Detail, apiErr.Errors[i].Detail)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestClockSkewError_Unwrap(t *testing.T) {
+	apiErr := &APIError{Type: "client_error", StatusCode: 401}
+	err := error(&ClockSkewError{Skew: 45 * time.Second, Err: apiErr})
+
+	var unwrapped *APIError
+	if !errors.As(err, &unwrapped) || unwrapped != apiErr {
+		t.Errorf("errors.As() did not find the wrapped *APIError")
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		want          error
+		wantRetryable bool
+		wantTemporary bool
+	}{
+		{"unauthorized", &APIError{StatusCode: 401, Type: "client_error"}, ErrUnauthorized, false, false},
+		{"forbidden", &APIError{StatusCode: 403, Type: "client_error"}, ErrForbidden, false, false},
+		{"not found", &StatusError{StatusCode: 404, Body: "Not Found"}, ErrNotFound, false, false},
+		{"rate limited", &APIError{StatusCode: 429, Type: "client_error"}, ErrRateLimited, true, true},
+		{"validation", &APIError{StatusCode: 400, Type: "validation_error"}, ErrValidation, false, false},
+		{"server", &APIError{StatusCode: 500, Type: "server_error"}, ErrServer, true, false},
+		{"unavailable", &StatusError{StatusCode: 503, Body: "<html>"}, ErrServer, true, true},
+		{"timestamp", &ClockSkewError{Err: &APIError{StatusCode: 401}}, ErrTimestampExpired, true, false},
+	}
+
+	sentinels := []error{ErrUnauthorized, ErrForbidden, ErrNotFound, ErrRateLimited, ErrValidation, ErrServer, ErrTimestampExpired}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, sentinel := range sentinels {
+				want := sentinel == tt.want
+				// A timestamp rejection is also an unauthorized error
+				if tt.want == ErrTimestampExpired && sentinel == ErrUnauthorized {
+					want = true
+				}
+				if got := errors.Is(tt.err, sentinel); got != want {
+					t.Errorf("errors.Is(%v) = %v, want %v", sentinel, got, want)
+				}
+			}
+
+			classified := tt.err.(interface {
+				Retryable() bool
+				Temporary() bool
+			})
+			if got := classified.Retryable(); got != tt.wantRetryable {
+				t.Errorf("Retryable() = %v, want %v", got, tt.wantRetryable)
+			}
+			if got := classified.Temporary(); got != tt.wantTemporary {
+				t.Errorf("Temporary() = %v, want %v", got, tt.wantTemporary)
+			}
+		})
+	}
+}
+
+func TestParseResponseError_RequestInfo(t *testing.T) {
+	info := RequestInfo{
+		Method:   "GET",
+		Path:     "/api/v1/crypto/trading/orders/",
+		Attempts: 3,
+		Header:   map[string][]string{"Retry-After": {"5"}},
+	}
+
+	err := ParseResponseError([]byte(`{"type":"client_error","errors":[]}`), 429, info)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("ParseResponseError() returned %T, want *APIError", err)
+	}
+	if apiErr.Method != "GET" || apiErr.Path != info.Path || apiErr.Attempts != 3 {
+		t.Errorf("RequestInfo = %+v, want %+v", apiErr.RequestInfo, info)
+	}
+	if apiErr.Header.Get("Retry-After") != "5" {
+		t.Errorf("Header Retry-After = %q, want %q", apiErr.Header.Get("Retry-After"), "5")
+	}
+}
+
+func TestNewValidationError(t *testing.T) {
+	req := &models.PlaceOrderRequest{
+		Symbol:        "BTC-USD",
+		ClientOrderID: "not-a-uuid",
+		Side:          "buy",
+		Type:          "limit",
+		LimitOrderConfig: &models.LimitOrderConfig{
+			AssetQuantity: models.MustParseDecimal("0.1"),
+			LimitPrice:    models.NewDecimalFromInt(-1),
+		},
+	}
+
+	apiErr := &APIError{
+		Type:       "validation_error",
+		StatusCode: 400,
+		Errors: []ErrorDetail{
+			{Attr: "client_order_id", Detail: "Must be a valid UUID."},
+			{Attr: "limit_order_config.limit_price", Detail: "Must be positive."},
+			{Attr: "time_in_force", Detail: "Invalid choice."},
+			{Attr: "non_field_errors", Detail: "Insufficient buying power."},
+			{Attr: "unknown_attr", Detail: "Something else."},
+		},
+	}
+
+	err := MapValidationError(apiErr, req)
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) {
+		t.Fatalf("MapValidationError() returned %T, want *ValidationError", err)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Error("errors.Is(ErrValidation) = false, want true")
+	}
+
+	wantFields := map[string]string{
+		"client_order_id":                "ClientOrderID",
+		"limit_order_config.limit_price": "LimitOrderConfig.LimitPrice",
+		"time_in_force":                  "LimitOrderConfig.TimeInForce",
+		"unknown_attr":                   "",
+	}
+	if len(valErr.Fields) != len(wantFields) {
+		t.Fatalf("len(Fields) = %d, want %d", len(valErr.Fields), len(wantFields))
+	}
+	for _, f := range valErr.Fields {
+		if want := wantFields[f.Attr]; f.Field != want {
+			t.Errorf("Field for %q = %q, want %q", f.Attr, f.Field, want)
+		}
+	}
+
+	if len(valErr.NonField) != 1 || valErr.NonField[0] != "Insufficient buying power." {
+		t.Errorf("NonField = %v, want [Insufficient buying power.]", valErr.NonField)
+	}
+	if got := valErr.FieldErrors("ClientOrderID"); len(got) != 1 {
+		t.Errorf("FieldErrors(ClientOrderID) = %v, want one error", got)
+	}
+}
+
+func TestFieldPath_OrdersFilter(t *testing.T) {
+	tests := map[string]string{
+		"created_at_start": "CreatedAtStart",
+		"state":            "State",
+		"limit":            "Limit",
+		"bogus":            "",
+	}
+	for attr, want := range tests {
+		if got := FieldPath(&models.OrdersFilter{}, attr); got != want {
+			t.Errorf("FieldPath(%q) = %q, want %q", attr, got, want)
+		}
+	}
+
+	// Non-validation errors pass through unchanged
+	notFound := &APIError{Type: "client_error", StatusCode: 404}
+	if err := MapValidationError(notFound, &models.OrdersFilter{}); err != notFound {
+		t.Errorf("MapValidationError() = %v, want unchanged error", err)
+	}
+}
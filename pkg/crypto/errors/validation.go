@@ -0,0 +1,207 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// nonFieldErrors is the attr used for errors not attributable to a field
+const nonFieldErrors = "non_field_errors"
+
+// FieldError is a validation error attributed to a single request field
+type FieldError struct {
+	// Attr is the attribute name reported by the API
+	Attr string
+	// Field is the Go field path in the request struct, such as
+	// "LimitOrderConfig.LimitPrice", or empty if the attr is not known
+	Field  string
+	Detail string
+}
+
+// ValidationError is a validation_error response whose details have been
+// mapped back to the fields of the request that caused it
+type ValidationError struct {
+	*APIError
+
+	// Fields holds errors attributed to request fields
+	Fields []FieldError
+	// NonField holds errors not attributable to any field
+	NonField []string
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.APIError
+}
+
+// FieldErrors returns the errors reported for a Go field path
+func (e *ValidationError) FieldErrors(field string) []string {
+	var details []string
+	for _, f := range e.Fields {
+		if f.Field == field {
+			details = append(details, f.Detail)
+		}
+	}
+	return details
+}
+
+// NewValidationError maps the details of a validation error to the fields of
+// request, which is the struct (or pointer to it) the request was built from.
+// Attributes are matched against json tags, or query tags for filters.
+func NewValidationError(apiErr *APIError, request interface{}) *ValidationError {
+	v := &ValidationError{APIError: apiErr}
+	for _, detail := range apiErr.Errors {
+		if detail.Attr == "" || detail.Attr == nonFieldErrors {
+			v.NonField = append(v.NonField, detail.Detail)
+			continue
+		}
+		v.Fields = append(v.Fields, FieldError{
+			Attr:   detail.Attr,
+			Field:  FieldPath(request, detail.Attr),
+			Detail: detail.Detail,
+		})
+	}
+	return v
+}
+
+// MapValidationError returns a *ValidationError for request if err is a
+// validation error response, and err unchanged otherwise
+func MapValidationError(err error, request interface{}) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.Is(ErrValidation) {
+		return err
+	}
+	return NewValidationError(apiErr, request)
+}
+
+// FieldPath returns the Go field path in request for an API attribute such
+// as "limit_order_config.limit_price". Attributes naming a nested field
+// without its parent, such as "limit_price", are resolved against the
+// nested structs that are set. It returns "" if no field matches.
+func FieldPath(request interface{}, attr string) string {
+	t, v := deref(reflect.TypeOf(request), reflect.ValueOf(request))
+	if t == nil || t.Kind() != reflect.Struct {
+		return ""
+	}
+
+	segments := strings.Split(strings.ReplaceAll(attr, "__", "."), ".")
+	if path := fieldPath(t, v, segments); path != "" {
+		return path
+	}
+
+	// Look one level down for attributes reported without their parent,
+	// preferring nested structs that are populated
+	if len(segments) == 1 {
+		var fallback string
+		for i := 0; i < t.NumField(); i++ {
+			ft, fv := deref(t.Field(i).Type, fieldValue(v, i))
+			if ft == nil || ft.Kind() != reflect.Struct {
+				continue
+			}
+			if path := fieldPath(ft, fv, segments); path != "" {
+				path = t.Field(i).Name + "." + path
+				if fv.IsValid() {
+					return path
+				}
+				if fallback == "" {
+					fallback = path
+				}
+			}
+		}
+		return fallback
+	}
+
+	return ""
+}
+
+// fieldPath resolves attribute segments against a struct type
+func fieldPath(t reflect.Type, v reflect.Value, segments []string) string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || tagName(field) != segments[0] {
+			continue
+		}
+		if len(segments) == 1 {
+			return field.Name
+		}
+
+		ft, fv := deref(field.Type, fieldValue(v, i))
+		if ft == nil || ft.Kind() != reflect.Struct {
+			return ""
+		}
+		if rest := fieldPath(ft, fv, segments[1:]); rest != "" {
+			return field.Name + "." + rest
+		}
+		return ""
+	}
+	return ""
+}
+
+// tagName returns the API name of a struct field from its json or query tag
+func tagName(field reflect.StructField) string {
+	for _, key := range []string{"json", "query"} {
+		if tag, ok := field.Tag.Lookup(key); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// deref follows pointers, returning an invalid value for nil pointers
+func deref(t reflect.Type, v reflect.Value) (reflect.Type, reflect.Value) {
+	if t == nil {
+		return nil, reflect.Value{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		if v.IsValid() {
+			if v.IsNil() {
+				v = reflect.Value{}
+			} else {
+				v = v.Elem()
+			}
+		}
+	}
+	return t, v
+}
+
+// fieldValue returns the i-th field of v, or an invalid value if v is invalid
+func fieldValue(v reflect.Value, i int) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Value{}
+	}
+	return v.Field(i)
+}
+
+// OrderSizeError is returned when an order's asset quantity, after rounding
+// to the trading pair's increment, falls outside the pair's minimum and
+// maximum order size. It is detected before the order is sent.
+type OrderSizeError struct {
+	Symbol string
+	// Attr is the API attribute, such as "limit_order_config.asset_quantity"
+	Attr string
+	// Field is the Go field path, such as "LimitOrderConfig.AssetQuantity"
+	Field string
+
+	Quantity models.Decimal
+	Min      models.Decimal
+	Max      models.Decimal
+}
+
+func (e *OrderSizeError) Error() string {
+	if !e.Max.IsZero() && e.Quantity.GreaterThan(e.Max) {
+		return fmt.Sprintf("%s: %s %s is above the maximum order size %s", e.Symbol, e.Attr, e.Quantity, e.Max)
+	}
+	return fmt.Sprintf("%s: %s %s is below the minimum order size %s", e.Symbol, e.Attr, e.Quantity, e.Min)
+}
+
+// Is reports whether the error matches ErrValidation
+func (e *OrderSizeError) Is(target error) bool {
+	return target == ErrValidation
+}
@@ -3,6 +3,6 @@ package models
 type AccountDetails struct {
 	AccountNumber        string `json:"account_number"`
 	Status              string `json:"status"`
-	BuyingPower         string `json:"buying_power"`
+	BuyingPower         Decimal `json:"buying_power"`
 	BuyingPowerCurrency string `json:"buying_power_currency"`
 }
\ No newline at end of file
@@ -0,0 +1,472 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an arbitrary-precision decimal number used for all prices and
+// quantities. It keeps the number of digits it was written with, so values
+// read from the API are marshaled back exactly as received.
+//
+// The zero value is 0. Decimals are immutable; arithmetic returns new values.
+// Use Equal or Cmp rather than == to compare them.
+type Decimal struct {
+	// coef is the unscaled value, nil for zero
+	coef *big.Int
+	// scale is the number of digits after the decimal point, never negative
+	scale int32
+}
+
+// RoundingMode selects how values are rounded to fewer digits
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest value, and halves away from zero
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, and halves to even
+	RoundHalfEven
+	// RoundDown rounds toward zero (truncation)
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundFloor rounds toward negative infinity
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity
+	RoundCeiling
+)
+
+// String returns the name of the rounding mode
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfUp:
+		return "half_up"
+	case RoundHalfEven:
+		return "half_even"
+	case RoundDown:
+		return "down"
+	case RoundUp:
+		return "up"
+	case RoundFloor:
+		return "floor"
+	case RoundCeiling:
+		return "ceiling"
+	}
+	return fmt.Sprintf("RoundingMode(%d)", int(m))
+}
+
+// maxExponent bounds the exponent and scale ParseDecimal accepts, so that
+// input such as "1e900000000" cannot make it build an enormous coefficient
+const maxExponent = 10000
+
+var (
+	bigOne = big.NewInt(1)
+	bigTen = big.NewInt(10)
+)
+
+// NewDecimal returns value × 10^exp, so NewDecimal(123, -2) is 1.23
+func NewDecimal(value int64, exp int32) Decimal {
+	coef := big.NewInt(value)
+	if exp >= 0 {
+		return Decimal{coef: coef.Mul(coef, pow10(exp))}
+	}
+	return Decimal{coef: coef, scale: -exp}
+}
+
+// NewDecimalFromInt returns the decimal value of an integer
+func NewDecimalFromInt(value int64) Decimal {
+	return NewDecimal(value, 0)
+}
+
+// NewDecimalFromFloat returns the shortest decimal that converts back to f.
+// It panics if f is NaN or infinite.
+func NewDecimalFromFloat(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(fmt.Sprintf("models: cannot convert %v to Decimal", f))
+	}
+	return MustParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// ParseDecimal parses a decimal string such as "45000.50", "-0.1" or "1e-8"
+func ParseDecimal(s string) (Decimal, error) {
+	original := s
+	if s == "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+	}
+
+	// Split off the exponent
+	var exp int64
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		exp, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+		}
+		if exp > maxExponent || exp < -maxExponent {
+			return Decimal{}, fmt.Errorf("invalid decimal %q: exponent out of range", original)
+		}
+		s = s[:i]
+	}
+
+	// Split off the sign
+	negative := false
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	digits := intPart + fracPart
+	if digits == "" {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+		}
+	}
+
+	coef, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", original)
+	}
+	if negative {
+		coef.Neg(coef)
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale > maxExponent || scale < -maxExponent {
+		return Decimal{}, fmt.Errorf("invalid decimal %q: exponent out of range", original)
+	}
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on invalid input. It is
+// intended for constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic("models: " + err.Error())
+	}
+	return d
+}
+
+// int returns the coefficient, treating nil as zero
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns the coefficient expressed at a scale at least d.scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	coef := new(big.Int).Set(d.int())
+	if scale > d.scale {
+		coef.Mul(coef, pow10(scale-d.scale))
+	}
+	return coef
+}
+
+// Add returns d + o
+func (d Decimal) Add(o Decimal) Decimal {
+	scale := max(d.scale, o.scale)
+	return Decimal{coef: new(big.Int).Add(d.rescale(scale), o.rescale(scale)), scale: scale}
+}
+
+// Sub returns d - o
+func (d Decimal) Sub(o Decimal) Decimal {
+	scale := max(d.scale, o.scale)
+	return Decimal{coef: new(big.Int).Sub(d.rescale(scale), o.rescale(scale)), scale: scale}
+}
+
+// Mul returns d × o
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.int(), o.int()), scale: d.scale + o.scale}
+}
+
+// Div returns d / o rounded half away from zero to the given number of
+// decimal places, at least zero. It panics if o is zero.
+func (d Decimal) Div(o Decimal, places int32) Decimal {
+	if o.IsZero() {
+		panic("models: division by zero")
+	}
+	if places < 0 {
+		places = 0
+	}
+
+	// d/o = (a/b) × 10^(o.scale-d.scale), computed at the requested scale
+	num := new(big.Int).Set(d.int())
+	den := new(big.Int).Set(o.int())
+	if k := places + o.scale - d.scale; k >= 0 {
+		num.Mul(num, pow10(k))
+	} else {
+		den.Mul(den, pow10(-k))
+	}
+
+	return Decimal{coef: quoRound(num, den, RoundHalfUp), scale: places}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Round rounds half away from zero to the given number of decimal places.
+// Values with fewer places are returned unchanged.
+func (d Decimal) Round(places int32) Decimal {
+	return d.RoundMode(places, RoundHalfUp)
+}
+
+// RoundMode rounds to the given number of decimal places using mode. Values
+// with fewer places are returned unchanged.
+func (d Decimal) RoundMode(places int32, mode RoundingMode) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if d.scale <= places {
+		return d
+	}
+	return Decimal{coef: quoRound(d.int(), pow10(d.scale-places), mode), scale: places}
+}
+
+// Quantize rounds d to a multiple of increment using mode, so that 0.123456
+// quantized to 0.0001 is 0.1234 when rounding down. The result has the
+// increment's scale. A zero or negative increment returns d unchanged.
+func (d Decimal) Quantize(increment Decimal, mode RoundingMode) Decimal {
+	if increment.Sign() <= 0 {
+		return d
+	}
+	scale := max(d.scale, increment.scale)
+	steps := quoRound(d.rescale(scale), increment.rescale(scale), mode)
+	return Decimal{coef: steps.Mul(steps, increment.int()), scale: increment.scale}
+}
+
+// Cmp returns -1, 0 or +1 depending on whether d is less than, equal to or
+// greater than o
+func (d Decimal) Cmp(o Decimal) int {
+	scale := max(d.scale, o.scale)
+	return d.rescale(scale).Cmp(o.rescale(scale))
+}
+
+// Equal reports whether d and o are numerically equal, so 1.0 equals 1.00
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
+
+// LessThan reports whether d < o
+func (d Decimal) LessThan(o Decimal) bool {
+	return d.Cmp(o) < 0
+}
+
+// GreaterThan reports whether d > o
+func (d Decimal) GreaterThan(o Decimal) bool {
+	return d.Cmp(o) > 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is zero
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Float64 returns the nearest float64 to d
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d in plain decimal notation with its full scale
+func (d Decimal) String() string {
+	coef := d.int()
+	digits := new(big.Int).Abs(coef).String()
+
+	if d.scale > 0 {
+		if pad := int(d.scale) - len(digits) + 1; pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		point := len(digits) - int(d.scale)
+		digits = digits[:point] + "." + digits[point:]
+	}
+
+	if coef.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// StringFixed returns d rounded or padded to exactly the given number of
+// decimal places
+func (d Decimal) StringFixed(places int32) string {
+	if places < 0 {
+		places = 0
+	}
+	r := d.Round(places)
+	return Decimal{coef: r.rescale(places), scale: places}.String()
+}
+
+// Format implements fmt.Formatter so that verbs such as %.2f work as they
+// do for floats, without losing precision
+func (d Decimal) Format(f fmt.State, verb rune) {
+	var s string
+	switch verb {
+	case 'f', 'F':
+		if prec, ok := f.Precision(); ok {
+			s = d.StringFixed(int32(prec))
+		} else {
+			s = d.String()
+		}
+	case 'v', 's':
+		s = d.String()
+	case 'q':
+		s = strconv.Quote(d.String())
+	case 'e', 'E', 'g', 'G':
+		prec, ok := f.Precision()
+		if !ok {
+			prec = -1
+		}
+		s = strconv.FormatFloat(d.Float64(), byte(verb), prec, 64)
+	default:
+		fmt.Fprintf(f, "%%!%c(models.Decimal=%s)", verb, d.String())
+		return
+	}
+
+	if f.Flag('+') && d.Sign() >= 0 {
+		s = "+" + s
+	}
+	if width, ok := f.Width(); ok && len(s) < width {
+		padding := strings.Repeat(" ", width-len(s))
+		if f.Flag('-') {
+			s += padding
+		} else {
+			s = padding + s
+		}
+	}
+	fmt.Fprint(f, s)
+}
+
+// MarshalJSON encodes d as a JSON string
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes a JSON string or number. Null and the empty string
+// decode to zero.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			*d = Decimal{}
+			return nil
+		}
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// optionalDecimal returns nil for zero so that omitempty drops the field
+func optionalDecimal(d Decimal) *Decimal {
+	if d.IsZero() {
+		return nil
+	}
+	return &d
+}
+
+// pow10 returns 10^n
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// quoRound returns num/den rounded using mode
+func quoRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	// QuoRem truncates toward zero; decide whether to step away from zero
+	negative := (num.Sign() < 0) != (den.Sign() < 0)
+	var away bool
+	switch mode {
+	case RoundDown:
+		away = false
+	case RoundUp:
+		away = true
+	case RoundFloor:
+		away = negative
+	case RoundCeiling:
+		away = !negative
+	default:
+		// Compare twice the remainder with the divisor to find the nearest
+		twiceRem := new(big.Int).Abs(rem)
+		twiceRem.Lsh(twiceRem, 1)
+		switch twiceRem.Cmp(new(big.Int).Abs(den)) {
+		case 1:
+			away = true
+		case 0:
+			away = mode == RoundHalfUp || quo.Bit(0) == 1
+		}
+	}
+
+	if away {
+		if negative {
+			quo.Sub(quo, bigOne)
+		} else {
+			quo.Add(quo, bigOne)
+		}
+	}
+	return quo
+}
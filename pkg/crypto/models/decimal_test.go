@@ -0,0 +1,232 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "45000.50", want: "45000.50"},
+		{input: "0.00000001", want: "0.00000001"},
+		{input: "-0.1", want: "-0.1"},
+		{input: "+3", want: "3"},
+		{input: ".5", want: "0.5"},
+		{input: "1e-8", want: "0.00000001"},
+		{input: "1.5E3", want: "1500"},
+		{input: "123456789012345678901234567890.123456789", want: "123456789012345678901234567890.123456789"},
+		{input: "", wantErr: true},
+		{input: "-", wantErr: true},
+		{input: "1.2.3", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "1e", wantErr: true},
+		{input: "1e10000", want: "1" + strings.Repeat("0", 10000)},
+		{input: "1e900000000", wantErr: true},
+		{input: "1e-900000000", wantErr: true},
+		{input: "0." + strings.Repeat("0", 10001), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDecimal(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDecimal(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("ParseDecimal(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a := MustParseDecimal("0.1")
+	b := MustParseDecimal("0.2")
+
+	if got := a.Add(b); got.String() != "0.3" {
+		t.Errorf("0.1 + 0.2 = %s, want 0.3", got)
+	}
+	if got := a.Sub(b); got.String() != "-0.1" {
+		t.Errorf("0.1 - 0.2 = %s, want -0.1", got)
+	}
+	if got := MustParseDecimal("45000.50").Mul(MustParseDecimal("0.0001")); got.String() != "4.500050" {
+		t.Errorf("45000.50 × 0.0001 = %s, want 4.500050", got)
+	}
+	if got := NewDecimalFromInt(2).Div(NewDecimalFromInt(3), 4); got.String() != "0.6667" {
+		t.Errorf("2 / 3 = %s, want 0.6667", got)
+	}
+	if got := NewDecimalFromInt(-1).Div(NewDecimalFromInt(8), 2); got.String() != "-0.13" {
+		t.Errorf("-1 / 8 = %s, want -0.13", got)
+	}
+	if got := MustParseDecimal("100").Div(MustParseDecimal("0.25"), 0); got.String() != "400" {
+		t.Errorf("100 / 0.25 = %s, want 400", got)
+	}
+	if got := NewDecimalFromInt(1234).Div(NewDecimalFromInt(1), -2); got.String() != "1234" {
+		t.Errorf("1234 / 1 to -2 places = %s, want 1234", got)
+	}
+	if got := MustParseDecimal("-1.5").Abs().Neg(); got.String() != "-1.5" {
+		t.Errorf("-|-1.5| = %s, want -1.5", got)
+	}
+
+	var zero Decimal
+	if got := zero.Add(a); !got.Equal(a) {
+		t.Errorf("0 + 0.1 = %s, want 0.1", got)
+	}
+}
+
+func TestDecimal_Compare(t *testing.T) {
+	if !MustParseDecimal("1.0").Equal(MustParseDecimal("1.00")) {
+		t.Error("1.0 should equal 1.00")
+	}
+	if !MustParseDecimal("0.1").LessThan(MustParseDecimal("0.10000001")) {
+		t.Error("0.1 should be less than 0.10000001")
+	}
+	if !MustParseDecimal("-1").LessThan(Decimal{}) || !MustParseDecimal("2").GreaterThan(MustParseDecimal("1.99")) {
+		t.Error("unexpected ordering")
+	}
+	if !(Decimal{}).IsZero() || !MustParseDecimal("0.000").IsZero() {
+		t.Error("zero values should report IsZero")
+	}
+}
+
+func TestDecimal_Round(t *testing.T) {
+	tests := []struct {
+		input  string
+		places int32
+		want   string
+	}{
+		{"1.005", 2, "1.01"},
+		{"1.004", 2, "1.00"},
+		{"-1.005", 2, "-1.01"},
+		{"1.5", 4, "1.5"},
+		{"99.99", 0, "100"},
+	}
+
+	for _, tt := range tests {
+		if got := MustParseDecimal(tt.input).Round(tt.places); got.String() != tt.want {
+			t.Errorf("Round(%s, %d) = %s, want %s", tt.input, tt.places, got, tt.want)
+		}
+	}
+
+	if got := MustParseDecimal("1.5").StringFixed(3); got != "1.500" {
+		t.Errorf("StringFixed(3) = %s, want 1.500", got)
+	}
+}
+
+func TestDecimal_Format(t *testing.T) {
+	d := MustParseDecimal("45000.505")
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%s", "45000.505"},
+		{"%v", "45000.505"},
+		{"%.2f", "45000.51"},
+		{"$%10.1f", "$   45000.5"},
+		{"%+.0f", "+45001"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, d); got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var v struct {
+		String Decimal `json:"string"`
+		Number Decimal `json:"number"`
+		Null   Decimal `json:"null"`
+		Empty  Decimal `json:"empty"`
+	}
+	data := `{"string":"0.00000001","number":45000.50,"null":null,"empty":""}`
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if v.String.String() != "0.00000001" || v.Number.String() != "45000.50" {
+		t.Errorf("decoded = %s, %s", v.String, v.Number)
+	}
+	if !v.Null.IsZero() || !v.Empty.IsZero() {
+		t.Errorf("null and empty should decode to zero, got %s, %s", v.Null, v.Empty)
+	}
+
+	out, err := json.Marshal(v.Number)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(out) != `"45000.50"` {
+		t.Errorf("json.Marshal() = %s, want %q", out, "45000.50")
+	}
+
+	if err := json.Unmarshal([]byte(`"not a number"`), &v.String); err == nil {
+		t.Error("expected error for invalid decimal string")
+	}
+}
+
+func TestBestBidAskResult_StringNumbers(t *testing.T) {
+	data := `{"symbol":"BTC-USD","price":"45000.123456789","bid_inclusive_of_sell_spread":"44990.1","sell_spread":"0.0002","ask_inclusive_of_buy_spread":"45010.2","buy_spread":"0.0002","timestamp":"2024-01-01T00:00:00Z"}`
+
+	var result BestBidAskResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if result.Price.String() != "45000.123456789" {
+		t.Errorf("Price = %s, want 45000.123456789", result.Price)
+	}
+	if result.SellSpread.String() != "0.0002" {
+		t.Errorf("SellSpread = %s, want 0.0002", result.SellSpread)
+	}
+}
+
+func TestOrderConfig_OmitsZeroQuantity(t *testing.T) {
+	data, err := json.Marshal(LimitOrderConfig{
+		QuoteAmount: MustParseDecimal("100.00"),
+		LimitPrice:  MustParseDecimal("45000.10"),
+		TimeInForce: "gtc",
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"quote_amount":"100.00","limit_price":"45000.10","time_in_force":"gtc"}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestDecimal_Quantize(t *testing.T) {
+	tests := []struct {
+		value     string
+		increment string
+		mode      RoundingMode
+		want      string
+	}{
+		{"0.123456", "0.0001", RoundDown, "0.1234"},
+		{"0.123456", "0.0001", RoundUp, "0.1235"},
+		{"0.12345", "0.0001", RoundHalfUp, "0.1235"},
+		{"0.12345", "0.0001", RoundHalfEven, "0.1234"},
+		{"0.12355", "0.0001", RoundHalfEven, "0.1236"},
+		{"-0.12345", "0.0001", RoundFloor, "-0.1235"},
+		{"-0.12345", "0.0001", RoundCeiling, "-0.1234"},
+		{"17", "5", RoundHalfUp, "15"},
+		{"0.07", "0.05", RoundHalfUp, "0.05"},
+		{"0.08", "0.05", RoundHalfUp, "0.10"},
+		{"45000.1", "0.01", RoundDown, "45000.10"},
+		{"1.5", "0", RoundDown, "1.5"},
+	}
+
+	for _, tt := range tests {
+		got := MustParseDecimal(tt.value).Quantize(MustParseDecimal(tt.increment), tt.mode)
+		if got.String() != tt.want {
+			t.Errorf("Quantize(%s, %s, %v) = %s, want %s", tt.value, tt.increment, tt.mode, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"bytes"
+	"strings"
+)
+
+// The enum types below decode any string the API sends, so a value added
+// by the server later is kept as is rather than failing the whole response.
+// IsValid reports whether a value is one this package knows about. Decoding
+// lower-cases values and trims surrounding space.
+
+// Side is the side of an order
+type Side string
+
+const (
+	SideBuy  Side = "buy"
+	SideSell Side = "sell"
+)
+
+// IsValid reports whether s is a known side
+func (s Side) IsValid() bool {
+	return s == SideBuy || s == SideSell
+}
+
+func (s Side) String() string {
+	return string(s)
+}
+
+// UnmarshalText accepts any side, normalizing its case
+func (s *Side) UnmarshalText(text []byte) error {
+	*s = Side(normalizeEnum(text))
+	return nil
+}
+
+// OrderType is the type of an order
+type OrderType string
+
+const (
+	OrderTypeMarket    OrderType = "market"
+	OrderTypeLimit     OrderType = "limit"
+	OrderTypeStopLoss  OrderType = "stop_loss"
+	OrderTypeStopLimit OrderType = "stop_limit"
+)
+
+// IsValid reports whether t is a known order type
+func (t OrderType) IsValid() bool {
+	switch t {
+	case OrderTypeMarket, OrderTypeLimit, OrderTypeStopLoss, OrderTypeStopLimit:
+		return true
+	}
+	return false
+}
+
+func (t OrderType) String() string {
+	return string(t)
+}
+
+// UnmarshalText accepts any order type, normalizing its case
+func (t *OrderType) UnmarshalText(text []byte) error {
+	*t = OrderType(normalizeEnum(text))
+	return nil
+}
+
+// OrderState is the state of an order
+type OrderState string
+
+const (
+	OrderStateOpen            OrderState = "open"
+	OrderStatePartiallyFilled OrderState = "partially_filled"
+	OrderStateFilled          OrderState = "filled"
+	OrderStateCanceled        OrderState = "canceled"
+	OrderStateFailed          OrderState = "failed"
+)
+
+// IsValid reports whether s is a known order state
+func (s OrderState) IsValid() bool {
+	switch s {
+	case OrderStateOpen, OrderStatePartiallyFilled, OrderStateFilled, OrderStateCanceled, OrderStateFailed:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether an order in state s will not change again.
+// Unknown states are not terminal, so callers waiting on an order keep
+// waiting rather than stopping early.
+func (s OrderState) IsTerminal() bool {
+	switch s {
+	case OrderStateFilled, OrderStateCanceled, OrderStateFailed:
+		return true
+	}
+	return false
+}
+
+func (s OrderState) String() string {
+	return string(s)
+}
+
+// UnmarshalText accepts any order state, normalizing its case
+func (s *OrderState) UnmarshalText(text []byte) error {
+	*s = OrderState(normalizeEnum(text))
+	return nil
+}
+
+// TimeInForce is how long an order stays open
+type TimeInForce string
+
+const (
+	// TimeInForceGTC is good till cancelled
+	TimeInForceGTC TimeInForce = "gtc"
+
+	// TimeInForceGFD is good for the day
+	TimeInForceGFD TimeInForce = "gfd"
+
+	// TimeInForceGFW is good for the week
+	TimeInForceGFW TimeInForce = "gfw"
+
+	// TimeInForceGFM is good for the month
+	TimeInForceGFM TimeInForce = "gfm"
+)
+
+// IsValid reports whether t is a known time in force
+func (t TimeInForce) IsValid() bool {
+	switch t {
+	case TimeInForceGTC, TimeInForceGFD, TimeInForceGFW, TimeInForceGFM:
+		return true
+	}
+	return false
+}
+
+func (t TimeInForce) String() string {
+	return string(t)
+}
+
+// UnmarshalText accepts any time in force, normalizing its case
+func (t *TimeInForce) UnmarshalText(text []byte) error {
+	*t = TimeInForce(normalizeEnum(text))
+	return nil
+}
+
+func normalizeEnum(text []byte) string {
+	return strings.ToLower(string(bytes.TrimSpace(text)))
+}
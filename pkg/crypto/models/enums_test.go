@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrderState_IsTerminal(t *testing.T) {
+	tests := []struct {
+		state    OrderState
+		valid    bool
+		terminal bool
+	}{
+		{OrderStateOpen, true, false},
+		{OrderStatePartiallyFilled, true, false},
+		{OrderStateFilled, true, true},
+		{OrderStateCanceled, true, true},
+		{OrderStateFailed, true, true},
+		{"expired", false, false},
+		{"", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.state.IsValid(); got != tt.valid {
+			t.Errorf("%q.IsValid() = %v, want %v", tt.state, got, tt.valid)
+		}
+		if got := tt.state.IsTerminal(); got != tt.terminal {
+			t.Errorf("%q.IsTerminal() = %v, want %v", tt.state, got, tt.terminal)
+		}
+	}
+}
+
+func TestEnums_IsValid(t *testing.T) {
+	if !SideSell.IsValid() || Side("short").IsValid() {
+		t.Error("Side.IsValid() is wrong")
+	}
+	if !OrderTypeStopLimit.IsValid() || OrderType("trailing_stop").IsValid() {
+		t.Error("OrderType.IsValid() is wrong")
+	}
+	if !TimeInForceGFD.IsValid() || TimeInForce("ioc").IsValid() {
+		t.Error("TimeInForce.IsValid() is wrong")
+	}
+}
+
+func TestEnums_JSON(t *testing.T) {
+	data := []byte(`{"side":"BUY","type":"limit","state":"pending_review","limit_order_config":{"limit_price":"1","time_in_force":" GTC "}}`)
+
+	var order Order
+	if err := json.Unmarshal(data, &order); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if order.Side != SideBuy || order.Type != OrderTypeLimit || order.LimitOrderConfig.TimeInForce != TimeInForceGTC {
+		t.Errorf("order = %+v, want normalized values", order)
+	}
+
+	// An unknown state is kept rather than failing the response
+	if order.State != "pending_review" || order.State.IsValid() {
+		t.Errorf("State = %q, want the unknown state kept", order.State)
+	}
+
+	out, err := json.Marshal(OrdersFilter{Side: SideSell, State: OrderStateFilled})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"side":"sell","state":"filled"}`; string(out) != want {
+		t.Errorf("json.Marshal() = %s, want %s", out, want)
+	}
+}
@@ -10,7 +10,7 @@ func TestAccountDetails_JSONMarshaling(t *testing.T) {
 	original := &AccountDetails{
 		AccountNumber:        "ACC123456",
 		Status:              "active",
-		BuyingPower:         "10000.50",
+		BuyingPower:         MustParseDecimal("10000.50"),
 		BuyingPowerCurrency: "USD",
 	}
 
@@ -26,10 +26,14 @@ func TestAccountDetails_JSONMarshaling(t *testing.T) {
 		t.Fatalf("json.Unmarshal() error = %v", err)
 	}
 
-	// Compare
-	if decoded != *original {
+	// Compare, checking the amount survives with its original precision
+	if decoded.AccountNumber != original.AccountNumber || decoded.Status != original.Status ||
+		decoded.BuyingPowerCurrency != original.BuyingPowerCurrency {
 		t.Errorf("decoded = %+v, want %+v", decoded, *original)
 	}
+	if decoded.BuyingPower.String() != "10000.50" {
+		t.Errorf("BuyingPower = %s, want 10000.50", decoded.BuyingPower)
+	}
 }
 
 func TestOrder_JSONMarshaling(t *testing.T) {
@@ -43,20 +47,20 @@ func TestOrder_JSONMarshaling(t *testing.T) {
 		Side:                "buy",
 		Type:                "limit",
 		State:               "open",
-		AveragePrice:        45000.50,
-		FilledAssetQuantity: 0.1,
-		CreatedAt:           "2023-10-31T20:57:50Z",
-		UpdatedAt:           "2023-10-31T20:58:00Z",
+		AveragePrice:        MustParseDecimal("45000.50"),
+		FilledAssetQuantity: MustParseDecimal("0.1"),
+		CreatedAt:           timestamp,
+		UpdatedAt:           timestamp.Add(10 * time.Second),
 		Executions: []Execution{
 			{
-				EffectivePrice: "45000.00",
-				Quantity:       "0.05",
+				EffectivePrice: MustParseDecimal("45000.00"),
+				Quantity:       MustParseDecimal("0.05"),
 				Timestamp:      timestamp,
 			},
 		},
 		LimitOrderConfig: &LimitOrderConfig{
-			AssetQuantity: 0.2,
-			LimitPrice:    45000,
+			AssetQuantity: MustParseDecimal("0.2"),
+			LimitPrice:    MustParseDecimal("45000"),
 			TimeInForce:   "gtc",
 		},
 	}
@@ -98,8 +102,8 @@ func TestOrder_JSONMarshaling(t *testing.T) {
 	if decoded.LimitOrderConfig == nil {
 		t.Fatal("LimitOrderConfig is nil")
 	}
-	if decoded.LimitOrderConfig.LimitPrice != original.LimitOrderConfig.LimitPrice {
-		t.Errorf("LimitOrderConfig.LimitPrice = %f, want %f", 
+	if !decoded.LimitOrderConfig.LimitPrice.Equal(original.LimitOrderConfig.LimitPrice) {
+		t.Errorf("LimitOrderConfig.LimitPrice = %s, want %s", 
 			decoded.LimitOrderConfig.LimitPrice, original.LimitOrderConfig.LimitPrice)
 	}
 }
@@ -118,7 +122,7 @@ func TestPlaceOrderRequest_Validation(t *testing.T) {
 				Side:          "buy",
 				Type:          "market",
 				MarketOrderConfig: &MarketOrderConfig{
-					AssetQuantity: 0.1,
+					AssetQuantity: MustParseDecimal("0.1"),
 				},
 			},
 			wantErr: false,
@@ -131,8 +135,8 @@ func TestPlaceOrderRequest_Validation(t *testing.T) {
 				Side:          "sell",
 				Type:          "limit",
 				LimitOrderConfig: &LimitOrderConfig{
-					AssetQuantity: 1.5,
-					LimitPrice:    2500.00,
+					AssetQuantity: MustParseDecimal("1.5"),
+					LimitPrice:    MustParseDecimal("2500.00"),
 					TimeInForce:   "gtc",
 				},
 			},
@@ -146,8 +150,8 @@ func TestPlaceOrderRequest_Validation(t *testing.T) {
 				Side:          "sell",
 				Type:          "stop_loss",
 				StopLossOrderConfig: &StopLossOrderConfig{
-					AssetQuantity: 0.5,
-					StopPrice:     40000.00,
+					AssetQuantity: MustParseDecimal("0.5"),
+					StopPrice:     MustParseDecimal("40000.00"),
 					TimeInForce:   "gtc",
 				},
 			},
@@ -161,9 +165,9 @@ func TestPlaceOrderRequest_Validation(t *testing.T) {
 				Side:          "buy",
 				Type:          "stop_limit",
 				StopLimitOrderConfig: &StopLimitOrderConfig{
-					AssetQuantity: 0.25,
-					StopPrice:     42000.00,
-					LimitPrice:    42500.00,
+					AssetQuantity: MustParseDecimal("0.25"),
+					StopPrice:     MustParseDecimal("42000.00"),
+					LimitPrice:    MustParseDecimal("42500.00"),
 					TimeInForce:   "ioc",
 				},
 			},
@@ -203,7 +207,7 @@ func TestPlaceOrderRequest_Validation(t *testing.T) {
 				"stop_limit_order_config",
 			}
 			
-			expectedConfig := tt.req.Type + "_order_config"
+			expectedConfig := string(tt.req.Type) + "_order_config"
 			foundExpected := false
 			
 			for _, field := range configFields {
@@ -231,10 +235,10 @@ func TestTradingPair_JSONMarshaling(t *testing.T) {
 	original := &TradingPair{
 		AssetCode:      "BTC",
 		QuoteCode:      "USD",
-		QuoteIncrement: "0.01",
-		AssetIncrement: "0.00000001",
-		MaxOrderSize:   "100",
-		MinOrderSize:   "0.0001",
+		QuoteIncrement: MustParseDecimal("0.01"),
+		AssetIncrement: MustParseDecimal("0.00000001"),
+		MaxOrderSize:   MustParseDecimal("100"),
+		MinOrderSize:   MustParseDecimal("0.0001"),
 		Status:         "tradable",
 		Symbol:         "BTC-USD",
 	}
@@ -250,8 +254,12 @@ func TestTradingPair_JSONMarshaling(t *testing.T) {
 		t.Fatalf("json.Unmarshal() error = %v", err)
 	}
 
-	if decoded != *original {
-		t.Errorf("decoded = %+v, want %+v", decoded, *original)
+	redecoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(redecoded) != string(data) {
+		t.Errorf("round trip = %s, want %s", redecoded, data)
 	}
 }
 
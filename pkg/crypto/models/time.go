@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeLayout formats times sent to the API: UTC, with fractional seconds to
+// the microsecond the API stores and trailing zeros dropped
+const timeLayout = "2006-01-02T15:04:05.999999Z07:00"
+
+// timeLayouts are the forms of timestamp the API has been seen to return.
+// Fractional seconds of any precision are accepted after the seconds in
+// each of them.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// ParseTime parses a timestamp from the API. It accepts RFC 3339 with or
+// without fractional seconds, offsets with or without a colon, a space in
+// place of the T, and no offset at all, which is taken to be UTC. An empty
+// string parses as the zero time.
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+}
+
+// FormatTime formats t for the API, in UTC to the microsecond. A time read
+// from a response formats to the same instant, so it can be passed straight
+// back in a filter.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// apiTime decodes a timestamp with ParseTime into the time.Time it points
+// to. Response types use it for their time fields when unmarshaling.
+type apiTime time.Time
+
+func (t *apiTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+	*t = apiTime(parsed)
+	return nil
+}
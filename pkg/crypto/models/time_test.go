@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2024, 4, 5, 21, 28, 14, 839553000, time.UTC)
+
+	for _, s := range []string{
+		"2024-04-05T21:28:14.839553Z",
+		"2024-04-05T17:28:14.839553-04:00",
+		"2024-04-05T17:28:14.839553-0400",
+		"2024-04-05 21:28:14.839553+00:00",
+		"2024-04-05T21:28:14.839553",
+		" 2024-04-05T21:28:14.839553000Z ",
+	} {
+		got, err := ParseTime(s)
+		if err != nil {
+			t.Errorf("ParseTime(%q) error = %v", s, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", s, got, want)
+		}
+	}
+
+	if got, err := ParseTime("2024-04-05T21:28:14Z"); err != nil || !got.Equal(want.Truncate(time.Second)) {
+		t.Errorf("ParseTime() without fractional seconds = %v, %v", got, err)
+	}
+	if got, err := ParseTime(""); err != nil || !got.IsZero() {
+		t.Errorf("ParseTime(\"\") = %v, %v, want the zero time", got, err)
+	}
+	if _, err := ParseTime("yesterday"); err == nil {
+		t.Error("ParseTime(\"yesterday\") succeeded")
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	eastern := time.FixedZone("EDT", -4*60*60)
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2024, 4, 5, 17, 28, 14, 0, eastern), "2024-04-05T21:28:14Z"},
+		{time.Date(2024, 4, 5, 21, 28, 14, 839553000, time.UTC), "2024-04-05T21:28:14.839553Z"},
+		{time.Date(2024, 4, 5, 21, 28, 14, 500000999, time.UTC), "2024-04-05T21:28:14.5Z"},
+	}
+	for _, tt := range tests {
+		if got := FormatTime(tt.t); got != tt.want {
+			t.Errorf("FormatTime(%v) = %s, want %s", tt.t, got, tt.want)
+		}
+	}
+
+	// A parsed time formats back to the same instant
+	parsed, _ := ParseTime("2024-04-05T17:28:14.839553-04:00")
+	if again, _ := ParseTime(FormatTime(parsed)); !again.Equal(parsed) {
+		t.Errorf("round trip = %v, want %v", again, parsed)
+	}
+}
+
+func TestTimeFields_UnmarshalJSON(t *testing.T) {
+	data := `{"id":"1","state":"filled","created_at":"2024-04-05T17:28:14.839553-04:00","updated_at":null,` +
+		`"executions":[{"quantity":"1","timestamp":"2024-04-05T21:28:15.1+0000"}]}`
+
+	var order Order
+	if err := json.Unmarshal([]byte(data), &order); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if want := time.Date(2024, 4, 5, 21, 28, 14, 839553000, time.UTC); !order.CreatedAt.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", order.CreatedAt, want)
+	}
+	if !order.UpdatedAt.IsZero() {
+		t.Errorf("UpdatedAt = %v, want the zero time for null", order.UpdatedAt)
+	}
+	if order.ID != "1" || order.State != OrderStateFilled || len(order.Executions) != 1 {
+		t.Fatalf("order = %+v", order)
+	}
+	if want := time.Date(2024, 4, 5, 21, 28, 15, 100000000, time.UTC); !order.Executions[0].Timestamp.Equal(want) {
+		t.Errorf("execution Timestamp = %v, want %v", order.Executions[0].Timestamp, want)
+	}
+
+	var quote BestBidAskResult
+	if err := json.Unmarshal([]byte(`{"symbol":"BTC-USD","price":"1","timestamp":"2024-04-05 21:28:14"}`), &quote); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if quote.Symbol != "BTC-USD" || !quote.Timestamp.Equal(time.Date(2024, 4, 5, 21, 28, 14, 0, time.UTC)) {
+		t.Errorf("quote = %+v", quote)
+	}
+
+	var estimate EstimatedPriceResult
+	if err := json.Unmarshal([]byte(`{"timestamp":"not a time"}`), &estimate); err == nil {
+		t.Error("json.Unmarshal() of an invalid timestamp succeeded")
+	}
+}
@@ -1,16 +1,20 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 type TradingPair struct {
-	AssetCode       string `json:"asset_code"`
-	QuoteCode       string `json:"quote_code"`
-	QuoteIncrement  string `json:"quote_increment"`
-	AssetIncrement  string `json:"asset_increment"`
-	MaxOrderSize    string `json:"max_order_size"`
-	MinOrderSize    string `json:"min_order_size"`
-	Status          string `json:"status"`
-	Symbol          string `json:"symbol"`
+	AssetCode      string  `json:"asset_code"`
+	QuoteCode      string  `json:"quote_code"`
+	QuoteIncrement Decimal `json:"quote_increment"`
+	AssetIncrement Decimal `json:"asset_increment"`
+	MaxOrderSize   Decimal `json:"max_order_size"`
+	MinOrderSize   Decimal `json:"min_order_size"`
+	Status         string  `json:"status"`
+	Symbol         string  `json:"symbol"`
 }
 
 type TradingPairsResponse struct {
@@ -22,8 +26,8 @@ type TradingPairsResponse struct {
 type Holding struct {
 	AccountNumber               string  `json:"account_number"`
 	AssetCode                   string  `json:"asset_code"`
-	TotalQuantity               float64 `json:"total_quantity"`
-	QuantityAvailableForTrading float64 `json:"quantity_available_for_trading"`
+	TotalQuantity               Decimal `json:"total_quantity"`
+	QuantityAvailableForTrading Decimal `json:"quantity_available_for_trading"`
 }
 
 type HoldingsResponse struct {
@@ -33,55 +37,55 @@ type HoldingsResponse struct {
 }
 
 type Execution struct {
-	EffectivePrice string    `json:"effective_price"`
-	Quantity       string    `json:"quantity"`
+	EffectivePrice Decimal   `json:"effective_price"`
+	Quantity       Decimal   `json:"quantity"`
 	Timestamp      time.Time `json:"timestamp"`
 }
 
 type MarketOrderConfig struct {
-	AssetQuantity float64 `json:"asset_quantity,omitempty"`
-	QuoteAmount   float64 `json:"quote_amount,omitempty"`
+	AssetQuantity Decimal `json:"asset_quantity,omitempty"`
+	QuoteAmount   Decimal `json:"quote_amount,omitempty"`
 }
 
 type LimitOrderConfig struct {
-	AssetQuantity float64 `json:"asset_quantity,omitempty"`
-	QuoteAmount   float64 `json:"quote_amount,omitempty"`
-	LimitPrice    float64 `json:"limit_price"`
-	TimeInForce   string  `json:"time_in_force"`
+	AssetQuantity Decimal     `json:"asset_quantity,omitempty"`
+	QuoteAmount   Decimal     `json:"quote_amount,omitempty"`
+	LimitPrice    Decimal     `json:"limit_price"`
+	TimeInForce   TimeInForce `json:"time_in_force"`
 }
 
 type StopLossOrderConfig struct {
-	AssetQuantity float64 `json:"asset_quantity,omitempty"`
-	QuoteAmount   float64 `json:"quote_amount,omitempty"`
-	StopPrice     float64 `json:"stop_price"`
-	TimeInForce   string  `json:"time_in_force"`
+	AssetQuantity Decimal     `json:"asset_quantity,omitempty"`
+	QuoteAmount   Decimal     `json:"quote_amount,omitempty"`
+	StopPrice     Decimal     `json:"stop_price"`
+	TimeInForce   TimeInForce `json:"time_in_force"`
 }
 
 type StopLimitOrderConfig struct {
-	AssetQuantity float64 `json:"asset_quantity,omitempty"`
-	QuoteAmount   float64 `json:"quote_amount,omitempty"`
-	LimitPrice    float64 `json:"limit_price"`
-	StopPrice     float64 `json:"stop_price"`
-	TimeInForce   string  `json:"time_in_force"`
+	AssetQuantity Decimal     `json:"asset_quantity,omitempty"`
+	QuoteAmount   Decimal     `json:"quote_amount,omitempty"`
+	LimitPrice    Decimal     `json:"limit_price"`
+	StopPrice     Decimal     `json:"stop_price"`
+	TimeInForce   TimeInForce `json:"time_in_force"`
 }
 
 type Order struct {
-	ID                    string                `json:"id"`
-	AccountNumber         string                `json:"account_number"`
-	Symbol                string                `json:"symbol"`
-	ClientOrderID         string                `json:"client_order_id"`
-	Side                  string                `json:"side"`
-	Executions            []Execution           `json:"executions"`
-	Type                  string                `json:"type"`
-	State                 string                `json:"state"`
-	AveragePrice          float64               `json:"average_price"`
-	FilledAssetQuantity   float64               `json:"filled_asset_quantity"`
-	CreatedAt             string                `json:"created_at"`
-	UpdatedAt             string                `json:"updated_at"`
-	MarketOrderConfig     *MarketOrderConfig    `json:"market_order_config,omitempty"`
-	LimitOrderConfig      *LimitOrderConfig     `json:"limit_order_config,omitempty"`
-	StopLossOrderConfig   *StopLossOrderConfig  `json:"stop_loss_order_config,omitempty"`
-	StopLimitOrderConfig  *StopLimitOrderConfig `json:"stop_limit_order_config,omitempty"`
+	ID                   string                `json:"id"`
+	AccountNumber        string                `json:"account_number"`
+	Symbol               string                `json:"symbol"`
+	ClientOrderID        string                `json:"client_order_id"`
+	Side                 Side                  `json:"side"`
+	Executions           []Execution           `json:"executions"`
+	Type                 OrderType             `json:"type"`
+	State                OrderState            `json:"state"`
+	AveragePrice         Decimal               `json:"average_price"`
+	FilledAssetQuantity  Decimal               `json:"filled_asset_quantity"`
+	CreatedAt            time.Time             `json:"created_at"`
+	UpdatedAt            time.Time             `json:"updated_at"`
+	MarketOrderConfig    *MarketOrderConfig    `json:"market_order_config,omitempty"`
+	LimitOrderConfig     *LimitOrderConfig     `json:"limit_order_config,omitempty"`
+	StopLossOrderConfig  *StopLossOrderConfig  `json:"stop_loss_order_config,omitempty"`
+	StopLimitOrderConfig *StopLimitOrderConfig `json:"stop_limit_order_config,omitempty"`
 }
 
 type OrdersResponse struct {
@@ -93,24 +97,192 @@ type OrdersResponse struct {
 type PlaceOrderRequest struct {
 	Symbol               string                `json:"symbol"`
 	ClientOrderID        string                `json:"client_order_id"`
-	Side                 string                `json:"side"`
-	Type                 string                `json:"type"`
+	Side                 Side                  `json:"side"`
+	Type                 OrderType             `json:"type"`
 	MarketOrderConfig    *MarketOrderConfig    `json:"market_order_config,omitempty"`
 	LimitOrderConfig     *LimitOrderConfig     `json:"limit_order_config,omitempty"`
 	StopLossOrderConfig  *StopLossOrderConfig  `json:"stop_loss_order_config,omitempty"`
 	StopLimitOrderConfig *StopLimitOrderConfig `json:"stop_limit_order_config,omitempty"`
 }
 
+// OrdersFilter holds the query parameters for listing orders. The query tags
+// name the corresponding API parameters, and the json tags use the same names
+// so filters can be saved in pagination checkpoints.
 type OrdersFilter struct {
-	CreatedAtStart *time.Time
-	CreatedAtEnd   *time.Time
-	UpdatedAtStart *time.Time
-	UpdatedAtEnd   *time.Time
-	Symbol         string
-	ID             string
-	Side           string
-	State          string
-	Type           string
-	Cursor         string
-	Limit          int
-}
\ No newline at end of file
+	CreatedAtStart *time.Time `json:"created_at_start,omitempty" query:"created_at_start"`
+	CreatedAtEnd   *time.Time `json:"created_at_end,omitempty" query:"created_at_end"`
+	UpdatedAtStart *time.Time `json:"updated_at_start,omitempty" query:"updated_at_start"`
+	UpdatedAtEnd   *time.Time `json:"updated_at_end,omitempty" query:"updated_at_end"`
+	Symbol         string     `json:"symbol,omitempty" query:"symbol"`
+	ID             string     `json:"id,omitempty" query:"id"`
+	Side           Side       `json:"side,omitempty" query:"side"`
+	State          OrderState `json:"state,omitempty" query:"state"`
+	Type           OrderType  `json:"type,omitempty" query:"type"`
+	Cursor         string     `json:"cursor,omitempty" query:"cursor"`
+	Limit          int        `json:"limit,omitempty" query:"limit"`
+}
+
+// UnmarshalJSON parses the timestamp with ParseTime
+func (e *Execution) UnmarshalJSON(data []byte) error {
+	type Alias Execution
+	return json.Unmarshal(data, &struct {
+		Timestamp *apiTime `json:"timestamp"`
+		*Alias
+	}{(*apiTime)(&e.Timestamp), (*Alias)(e)})
+}
+
+// UnmarshalJSON parses the created and updated times with ParseTime
+func (o *Order) UnmarshalJSON(data []byte) error {
+	type Alias Order
+	return json.Unmarshal(data, &struct {
+		CreatedAt *apiTime `json:"created_at"`
+		UpdatedAt *apiTime `json:"updated_at"`
+		*Alias
+	}{(*apiTime)(&o.CreatedAt), (*apiTime)(&o.UpdatedAt), (*Alias)(o)})
+}
+
+// MarshalJSON omits whichever of AssetQuantity and QuoteAmount is zero
+func (c MarketOrderConfig) MarshalJSON() ([]byte, error) {
+	type Alias MarketOrderConfig
+	return json.Marshal(struct {
+		AssetQuantity *Decimal `json:"asset_quantity,omitempty"`
+		QuoteAmount   *Decimal `json:"quote_amount,omitempty"`
+		Alias
+	}{optionalDecimal(c.AssetQuantity), optionalDecimal(c.QuoteAmount), Alias(c)})
+}
+
+// MarshalJSON omits whichever of AssetQuantity and QuoteAmount is zero
+func (c LimitOrderConfig) MarshalJSON() ([]byte, error) {
+	type Alias LimitOrderConfig
+	return json.Marshal(struct {
+		AssetQuantity *Decimal `json:"asset_quantity,omitempty"`
+		QuoteAmount   *Decimal `json:"quote_amount,omitempty"`
+		Alias
+	}{optionalDecimal(c.AssetQuantity), optionalDecimal(c.QuoteAmount), Alias(c)})
+}
+
+// MarshalJSON omits whichever of AssetQuantity and QuoteAmount is zero
+func (c StopLossOrderConfig) MarshalJSON() ([]byte, error) {
+	type Alias StopLossOrderConfig
+	return json.Marshal(struct {
+		AssetQuantity *Decimal `json:"asset_quantity,omitempty"`
+		QuoteAmount   *Decimal `json:"quote_amount,omitempty"`
+		Alias
+	}{optionalDecimal(c.AssetQuantity), optionalDecimal(c.QuoteAmount), Alias(c)})
+}
+
+// MarshalJSON omits whichever of AssetQuantity and QuoteAmount is zero
+func (c StopLimitOrderConfig) MarshalJSON() ([]byte, error) {
+	type Alias StopLimitOrderConfig
+	return json.Marshal(struct {
+		AssetQuantity *Decimal `json:"asset_quantity,omitempty"`
+		QuoteAmount   *Decimal `json:"quote_amount,omitempty"`
+		Alias
+	}{optionalDecimal(c.AssetQuantity), optionalDecimal(c.QuoteAmount), Alias(c)})
+}
+
+// Clone returns a copy of the request that shares no configs with it
+func (r *PlaceOrderRequest) Clone() *PlaceOrderRequest {
+	out := *r
+	if r.MarketOrderConfig != nil {
+		config := *r.MarketOrderConfig
+		out.MarketOrderConfig = &config
+	}
+	if r.LimitOrderConfig != nil {
+		config := *r.LimitOrderConfig
+		out.LimitOrderConfig = &config
+	}
+	if r.StopLossOrderConfig != nil {
+		config := *r.StopLossOrderConfig
+		out.StopLossOrderConfig = &config
+	}
+	if r.StopLimitOrderConfig != nil {
+		config := *r.StopLimitOrderConfig
+		out.StopLimitOrderConfig = &config
+	}
+	return &out
+}
+
+// Validate checks that the request has a symbol, a known side, and a config
+// matching its type with exactly one of an asset quantity and a quote
+// amount and, if set, a known time in force. It does not modify the
+// request.
+func (r *PlaceOrderRequest) Validate() error {
+	if r.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	if !r.Side.IsValid() {
+		return fmt.Errorf("invalid side: must be 'buy' or 'sell'")
+	}
+
+	// Validate order type and corresponding config
+	switch r.Type {
+	case OrderTypeMarket:
+		if r.MarketOrderConfig == nil {
+			return fmt.Errorf("market_order_config is required for market orders")
+		}
+		return validateAmounts(r.MarketOrderConfig.AssetQuantity, r.MarketOrderConfig.QuoteAmount)
+	case OrderTypeLimit:
+		if r.LimitOrderConfig == nil {
+			return fmt.Errorf("limit_order_config is required for limit orders")
+		}
+		if r.LimitOrderConfig.LimitPrice.Sign() <= 0 {
+			return fmt.Errorf("limit_price must be greater than 0")
+		}
+		if err := validateTimeInForce(r.LimitOrderConfig.TimeInForce); err != nil {
+			return err
+		}
+		return validateAmounts(r.LimitOrderConfig.AssetQuantity, r.LimitOrderConfig.QuoteAmount)
+	case OrderTypeStopLoss:
+		if r.StopLossOrderConfig == nil {
+			return fmt.Errorf("stop_loss_order_config is required for stop loss orders")
+		}
+		if r.StopLossOrderConfig.StopPrice.Sign() <= 0 {
+			return fmt.Errorf("stop_price must be greater than 0")
+		}
+		if err := validateTimeInForce(r.StopLossOrderConfig.TimeInForce); err != nil {
+			return err
+		}
+		return validateAmounts(r.StopLossOrderConfig.AssetQuantity, r.StopLossOrderConfig.QuoteAmount)
+	case OrderTypeStopLimit:
+		if r.StopLimitOrderConfig == nil {
+			return fmt.Errorf("stop_limit_order_config is required for stop limit orders")
+		}
+		if r.StopLimitOrderConfig.StopPrice.Sign() <= 0 {
+			return fmt.Errorf("stop_price must be greater than 0")
+		}
+		if r.StopLimitOrderConfig.LimitPrice.Sign() <= 0 {
+			return fmt.Errorf("limit_price must be greater than 0")
+		}
+		if err := validateTimeInForce(r.StopLimitOrderConfig.TimeInForce); err != nil {
+			return err
+		}
+		return validateAmounts(r.StopLimitOrderConfig.AssetQuantity, r.StopLimitOrderConfig.QuoteAmount)
+	default:
+		return fmt.Errorf("invalid order type: must be 'market', 'limit', 'stop_loss', or 'stop_limit'")
+	}
+}
+
+// validateTimeInForce checks that tif is empty, leaving the default to the
+// caller, or a known value
+func validateTimeInForce(tif TimeInForce) error {
+	if tif != "" && !tif.IsValid() {
+		return fmt.Errorf("invalid time_in_force %q: must be 'gtc', 'gfd', 'gfw' or 'gfm'", tif)
+	}
+	return nil
+}
+
+// validateAmounts checks that exactly one of an asset quantity and a quote
+// amount is set, and that it is positive
+func validateAmounts(assetQuantity, quoteAmount Decimal) error {
+	if assetQuantity.IsZero() && quoteAmount.IsZero() {
+		return fmt.Errorf("either asset_quantity or quote_amount must be specified")
+	}
+	if !assetQuantity.IsZero() && !quoteAmount.IsZero() {
+		return fmt.Errorf("only one of asset_quantity or quote_amount can be specified")
+	}
+	if assetQuantity.Sign() < 0 || quoteAmount.Sign() < 0 {
+		return fmt.Errorf("asset_quantity and quote_amount must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+// Builder assembles an order one setting at a time, for example
+//
+//	req, err := order.Buy("BTC-USD").Quantity(q).Limit(p).GTC().Build()
+//
+// The order type follows from the prices set: none makes a market order,
+// Limit a limit order, Stop a stop loss order, and both a stop limit order.
+// Nothing is checked until Build, which reports every problem at once.
+type Builder struct {
+	symbol        string
+	side          models.Side
+	clientOrderID string
+	timeInForce   models.TimeInForce
+
+	quantity    *models.Decimal
+	quoteAmount *models.Decimal
+	limitPrice  *models.Decimal
+	stopPrice   *models.Decimal
+	market      bool
+}
+
+// Buy starts a buy order for symbol
+func Buy(symbol string) *Builder {
+	return &Builder{symbol: symbol, side: models.SideBuy}
+}
+
+// Sell starts a sell order for symbol
+func Sell(symbol string) *Builder {
+	return &Builder{symbol: symbol, side: models.SideSell}
+}
+
+// Quantity sets the amount of the asset to trade
+func (b *Builder) Quantity(q models.Decimal) *Builder {
+	b.quantity = &q
+	return b
+}
+
+// QuoteAmount sets the amount of the quote currency to trade, instead of a
+// quantity of the asset
+func (b *Builder) QuoteAmount(a models.Decimal) *Builder {
+	b.quoteAmount = &a
+	return b
+}
+
+// Market makes the order a market order. It is the default when no limit
+// or stop price is set, so it only serves to make that explicit.
+func (b *Builder) Market() *Builder {
+	b.market = true
+	return b
+}
+
+// Limit sets the limit price
+func (b *Builder) Limit(price models.Decimal) *Builder {
+	b.limitPrice = &price
+	return b
+}
+
+// Stop sets the stop price
+func (b *Builder) Stop(price models.Decimal) *Builder {
+	b.stopPrice = &price
+	return b
+}
+
+// GTC makes the order good till cancelled
+func (b *Builder) GTC() *Builder {
+	return b.TimeInForce(models.TimeInForceGTC)
+}
+
+// TimeInForce sets the order's time in force. Orders other than market
+// orders are good till cancelled if it is not set.
+func (b *Builder) TimeInForce(tif models.TimeInForce) *Builder {
+	b.timeInForce = tif
+	return b
+}
+
+// ClientOrderID sets the ID used to recognize the order if it is sent more
+// than once. Build generates a UUID if it is not set.
+func (b *Builder) ClientOrderID(id string) *Builder {
+	b.clientOrderID = id
+	return b
+}
+
+// Build validates the order and returns it as a Request. All problems are
+// reported together in one error wrapping errors.ErrValidation.
+func (b *Builder) Build() (*Request, error) {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(b.symbol) == "" {
+		problem("symbol is required")
+	}
+
+	switch {
+	case b.quantity != nil && b.quoteAmount != nil:
+		problem("quantity and quote amount cannot both be set")
+	case b.quantity == nil && b.quoteAmount == nil:
+		problem("a quantity or quote amount is required")
+	case b.quantity != nil && b.quantity.Sign() <= 0:
+		problem("quantity must be greater than 0, got %s", b.quantity)
+	case b.quoteAmount != nil && b.quoteAmount.Sign() <= 0:
+		problem("quote amount must be greater than 0, got %s", b.quoteAmount)
+	}
+
+	if b.market {
+		if b.limitPrice != nil {
+			problem("a market order cannot have a limit price")
+		}
+		if b.stopPrice != nil {
+			problem("a market order cannot have a stop price")
+		}
+	}
+	if b.limitPrice != nil && b.limitPrice.Sign() <= 0 {
+		problem("limit price must be greater than 0, got %s", b.limitPrice)
+	}
+	if b.stopPrice != nil && b.stopPrice.Sign() <= 0 {
+		problem("stop price must be greater than 0, got %s", b.stopPrice)
+	}
+	if b.timeInForce != "" {
+		if b.limitPrice == nil && b.stopPrice == nil {
+			problem("a market order does not take a time in force")
+		} else if !b.timeInForce.IsValid() {
+			problem("unknown time in force %q", b.timeInForce)
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", errors.ErrValidation, strings.Join(problems, "; "))
+	}
+
+	req := b.request()
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", errors.ErrValidation, err)
+	}
+	return &Request{req: *req}, nil
+}
+
+// request assembles the PlaceOrderRequest for a builder that has passed
+// its checks
+func (b *Builder) request() *models.PlaceOrderRequest {
+	var quantity, quoteAmount models.Decimal
+	if b.quantity != nil {
+		quantity = *b.quantity
+	}
+	if b.quoteAmount != nil {
+		quoteAmount = *b.quoteAmount
+	}
+
+	tif := b.timeInForce
+	if tif == "" {
+		tif = models.TimeInForceGTC
+	}
+
+	clientOrderID := b.clientOrderID
+	if clientOrderID == "" {
+		clientOrderID = uuid.New().String()
+	}
+
+	req := &models.PlaceOrderRequest{
+		Symbol:        strings.ToUpper(strings.TrimSpace(b.symbol)),
+		ClientOrderID: clientOrderID,
+		Side:          b.side,
+	}
+
+	switch {
+	case b.limitPrice != nil && b.stopPrice != nil:
+		req.Type = models.OrderTypeStopLimit
+		req.StopLimitOrderConfig = &models.StopLimitOrderConfig{
+			AssetQuantity: quantity,
+			QuoteAmount:   quoteAmount,
+			LimitPrice:    *b.limitPrice,
+			StopPrice:     *b.stopPrice,
+			TimeInForce:   tif,
+		}
+	case b.limitPrice != nil:
+		req.Type = models.OrderTypeLimit
+		req.LimitOrderConfig = &models.LimitOrderConfig{
+			AssetQuantity: quantity,
+			QuoteAmount:   quoteAmount,
+			LimitPrice:    *b.limitPrice,
+			TimeInForce:   tif,
+		}
+	case b.stopPrice != nil:
+		req.Type = models.OrderTypeStopLoss
+		req.StopLossOrderConfig = &models.StopLossOrderConfig{
+			AssetQuantity: quantity,
+			QuoteAmount:   quoteAmount,
+			StopPrice:     *b.stopPrice,
+			TimeInForce:   tif,
+		}
+	default:
+		req.Type = models.OrderTypeMarket
+		req.MarketOrderConfig = &models.MarketOrderConfig{
+			AssetQuantity: quantity,
+			QuoteAmount:   quoteAmount,
+		}
+	}
+	return req
+}
+
+// Request is a validated order. It cannot be changed once built, and the
+// PlaceOrderRequest it returns is a fresh copy each time.
+type Request struct {
+	req models.PlaceOrderRequest
+}
+
+// Symbol returns the trading pair, in upper case
+func (r *Request) Symbol() string {
+	return r.req.Symbol
+}
+
+// Side returns the order's side
+func (r *Request) Side() models.Side {
+	return r.req.Side
+}
+
+// Type returns the order type implied by the prices set
+func (r *Request) Type() models.OrderType {
+	return r.req.Type
+}
+
+// ClientOrderID returns the order's client order ID
+func (r *Request) ClientOrderID() string {
+	return r.req.ClientOrderID
+}
+
+// PlaceOrderRequest returns a copy of the order to pass to PlaceOrder
+func (r *Request) PlaceOrderRequest() *models.PlaceOrderRequest {
+	return r.req.Clone()
+}
@@ -0,0 +1,116 @@
+package order
+
+import (
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/errors"
+	"github.com/rizome-dev/go-robinhood/pkg/crypto/models"
+)
+
+func TestBuild_OrderTypes(t *testing.T) {
+	q := models.MustParseDecimal("0.5")
+	p := models.MustParseDecimal("50000")
+	s := models.MustParseDecimal("48000")
+
+	tests := []struct {
+		name    string
+		builder *Builder
+		want    models.OrderType
+	}{
+		{"market", Buy("btc-usd").Quantity(q), models.OrderTypeMarket},
+		{"explicit market", Sell("BTC-USD").QuoteAmount(p).Market(), models.OrderTypeMarket},
+		{"limit", Buy("BTC-USD").Quantity(q).Limit(p).GTC(), models.OrderTypeLimit},
+		{"stop loss", Sell("BTC-USD").Quantity(q).Stop(s), models.OrderTypeStopLoss},
+		{"stop limit", Sell("BTC-USD").Quantity(q).Stop(s).Limit(p), models.OrderTypeStopLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if req.Type() != tt.want || req.Symbol() != "BTC-USD" {
+				t.Errorf("Type(), Symbol() = %q, %q, want %q, BTC-USD", req.Type(), req.Symbol(), tt.want)
+			}
+			if _, err := uuid.Parse(req.ClientOrderID()); err != nil {
+				t.Errorf("ClientOrderID() = %q, want a UUID", req.ClientOrderID())
+			}
+			if err := req.PlaceOrderRequest().Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestBuild_Configs(t *testing.T) {
+	req, err := Sell("ETH-USD").
+		Quantity(models.MustParseDecimal("1.5")).
+		Stop(models.MustParseDecimal("2400")).
+		Limit(models.MustParseDecimal("2390")).
+		ClientOrderID("my-order").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	placed := req.PlaceOrderRequest()
+	config := placed.StopLimitOrderConfig
+	if placed.ClientOrderID != "my-order" || placed.Side != "sell" || config == nil {
+		t.Fatalf("request = %+v", placed)
+	}
+	if config.AssetQuantity.String() != "1.5" || config.StopPrice.String() != "2400" || config.LimitPrice.String() != "2390" {
+		t.Errorf("config = %+v", config)
+	}
+	if config.TimeInForce != "gtc" {
+		t.Errorf("TimeInForce = %q, want gtc by default", config.TimeInForce)
+	}
+
+	// Changing a returned copy does not change the request
+	config.LimitPrice = models.NewDecimalFromInt(1)
+	if got := req.PlaceOrderRequest().StopLimitOrderConfig.LimitPrice.String(); got != "2390" {
+		t.Errorf("LimitPrice = %s after changing a copy, want 2390", got)
+	}
+}
+
+func TestBuild_Errors(t *testing.T) {
+	q := models.MustParseDecimal("1")
+
+	tests := []struct {
+		name    string
+		builder *Builder
+		want    []string
+	}{
+		{"no amount", Buy("BTC-USD").Limit(q), []string{"a quantity or quote amount is required"}},
+		{"both amounts", Buy("BTC-USD").Quantity(q).QuoteAmount(q), []string{"cannot both be set"}},
+		{"no symbol", Buy(" ").Quantity(q), []string{"symbol is required"}},
+		{"negative quantity", Buy("BTC-USD").Quantity(models.MustParseDecimal("-1")), []string{"quantity must be greater than 0"}},
+		{"zero price", Buy("BTC-USD").Quantity(q).Limit(models.Decimal{}), []string{"limit price must be greater than 0"}},
+		{"market with prices", Buy("BTC-USD").Quantity(q).Market().Limit(q).Stop(q), []string{
+			"market order cannot have a limit price",
+			"market order cannot have a stop price",
+		}},
+		{"market with time in force", Buy("BTC-USD").Quantity(q).GTC(), []string{"does not take a time in force"}},
+		{"unknown time in force", Buy("BTC-USD").Quantity(q).Limit(q).TimeInForce("ioc"), []string{`unknown time in force "ioc"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.builder.Build()
+			if err == nil {
+				t.Fatalf("Build() = %+v, want an error", req)
+			}
+			if !stderrors.Is(err, errors.ErrValidation) {
+				t.Errorf("Build() error = %v, want ErrValidation", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Build() error = %q, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}